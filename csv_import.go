@@ -0,0 +1,548 @@
+// Package kit provides utilities for handling HTTP request parsing and validation.
+// This file defines the streaming CSV importer of bulk uploads, which maps the header row to the `csv` tags of a
+// struct, parses the cells following the pt-BR conventions of spreadsheets exported as CSV, and validates each
+// row, collecting violations addressed by row and column.
+
+package kit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Tags of the violations reported by the CSV importer, besides the ones of the validation rules.
+const (
+	TagCSVSyntax        = "csv_syntax"
+	TagCSVType          = "csv_type"
+	TagCSVMissingColumn = "csv_missing_column"
+)
+
+// CSV cell kinds, used as the params of csv_type violations.
+const (
+	csvNumber  = "number"
+	csvInteger = "integer"
+	csvBoolean = "boolean"
+	csvDate    = "date"
+)
+
+// CodeImportValidation is the error code of imports with invalid rows, whose violations are its details.
+var CodeImportValidation = ErrorCode{
+	Slug:   "import-validation",
+	Status: http.StatusUnprocessableEntity,
+	Messages: map[string]string{
+		LocalePtBR: "falha na validação da importação",
+		LocaleEn:   "import validation failed",
+		LocaleEs:   "la validación de la importación falló",
+	},
+}
+
+func init() {
+	DefaultErrorCatalog.MustRegister(CodeImportValidation)
+}
+
+// csvMessages are the message templates of the CSV importer violations per locale.
+// Templates receive the column label as {0} and the param as {1}; the row is prepended by csvImporter.report.
+var csvMessages = map[string]map[string]string{
+	TagCSVSyntax: {
+		LocalePtBR: "registro malformado",
+		LocaleEn:   "malformed record",
+		LocaleEs:   "registro mal formado",
+	},
+	TagCSVType: {
+		LocalePtBR: "{0} deve ser {1}",
+		LocaleEn:   "{0} must be {1}",
+		LocaleEs:   "{0} debe ser {1}",
+	},
+	TagCSVMissingColumn: {
+		LocalePtBR: "coluna {0} ausente",
+		LocaleEn:   "missing column {0}",
+		LocaleEs:   "columna {0} ausente",
+	},
+}
+
+// csvRowPrefixes are the prefixes of the messages of the violations of a row, which receive the row as {0}.
+var csvRowPrefixes = map[string]string{
+	LocalePtBR: "Linha {0}: ",
+	LocaleEn:   "Row {0}: ",
+	LocaleEs:   "Fila {0}: ",
+}
+
+// csvKindNames are the names of the CSV cell kinds per locale.
+var csvKindNames = map[string]map[string]string{
+	csvNumber:  {LocalePtBR: "um número", LocaleEn: "a number", LocaleEs: "un número"},
+	csvInteger: {LocalePtBR: "um número inteiro", LocaleEn: "an integer", LocaleEs: "un número entero"},
+	csvBoolean: {LocalePtBR: "sim ou não", LocaleEn: "yes or no", LocaleEs: "sí o no"},
+	csvDate:    {LocalePtBR: "uma data", LocaleEn: "a date", LocaleEs: "una fecha"},
+}
+
+// csvBooleans are the accepted spellings of boolean cells, compared case-insensitively.
+var csvBooleans = map[string]bool{
+	"1": true, "true": true, "sim": true, "s": true, "yes": true, "y": true, "x": true,
+	"0": false, "false": false, "não": false, "nao": false, "n": false, "no": false,
+}
+
+// utf8BOM is the byte order mark spreadsheet applications write at the start of UTF-8 CSV files.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// CSVConfig configures ImportCSV. Its zero value follows the conventions of spreadsheets exported as CSV in pt-BR.
+type CSVConfig struct {
+	Comma            rune     // Field separator. Defaults to the most frequent of ';' and ',' in the header row.
+	DecimalSeparator rune     // Decimal separator, the other one of ',' and '.' separating groups of 3 digits. Defaults to ',', or '.' when Comma is ','.
+	DateLayouts      []string // Layouts of time.Time cells, tried in order. Defaults to dd/mm/yyyy, then yyyy-mm-dd.
+	MaxErrors        int      // Violations collected before the import stops reading rows. Defaults to 100.
+	Locale           string   // Locale of the violation messages. Defaults to DefaultLocale.
+}
+
+// CSVSummary summarizes the rows read by ImportCSV.
+type CSVSummary struct {
+	Rows        int  `json:"rows"`         // Data rows read, excluding the header row and blank rows.
+	ValidRows   int  `json:"valid_rows"`   // Rows passed to the row function.
+	InvalidRows int  `json:"invalid_rows"` // Rows with violations.
+	Truncated   bool `json:"truncated"`    // Whether the import stopped at MaxErrors violations with rows left unread.
+}
+
+// ImportCSV reads the CSV from r row by row, decoding each row into a T according to the `csv` tags of its fields,
+// normalizing and validating it with v, and calling fn with the row number, counted from 1 at the header row as
+// spreadsheets do, and the record of each valid row.
+//
+// Columns are matched to the tags case-insensitively and in any order; unknown columns are ignored and columns
+// tagged with the `optional` option, e.g. `csv:"email,optional"`, may be missing. Empty cells leave fields at their
+// zero value, or nil for pointers, so that `required` rules report them. Cells that are not valid UTF-8 are decoded
+// as Windows-1252, the encoding of CSV files saved by Excel in pt-BR.
+//
+// Rows are validated with ctx when v is a ContextValidator and translated to the config locale when v is a
+// LocalizedValidator. fn is called for the valid rows as they are read, even when other rows turn out invalid;
+// callers importing all rows or none should run the import in a transaction. An error returned by fn stops the
+// import and is returned as is.
+//
+// When rows are invalid, ImportCSV returns an import-validation HTTPError (422) whose details are the violations,
+// with their Row and their column as Field, and whose metadata is the summary.
+func ImportCSV[T any](ctx context.Context, r io.Reader, v Validator, config CSVConfig, fn func(row int, record *T) error) (CSVSummary, error) {
+	columns, err := csvColumnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return CSVSummary{}, err
+	}
+
+	importer, err := newCSVImporter(r, config)
+	if err != nil {
+		return CSVSummary{}, err
+	}
+
+	header, err := importer.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return CSVSummary{}, CodeBadInput.New(errors.New("csv: missing header row"))
+	}
+	if err != nil {
+		return CSVSummary{}, CodeBadInput.New(err)
+	}
+	indexes := importer.matchHeader(header, columns)
+	if len(importer.violations) > 0 {
+		return importer.summary, importer.err()
+	}
+
+	for row := 2; len(importer.violations) < importer.config.MaxErrors; row++ {
+		cells, err := importer.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return importer.summary, importer.err()
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			importer.summary.Rows++
+			importer.summary.InvalidRows++
+			importer.report(Violation{Row: row, Tag: TagCSVSyntax}, "")
+			continue
+		}
+		if err != nil {
+			return importer.summary, err
+		}
+		if isBlankRecord(cells) {
+			continue
+		}
+		importer.summary.Rows++
+
+		var record T
+		if !importer.decode(row, cells, indexes, columns, reflect.ValueOf(&record).Elem()) {
+			importer.summary.InvalidRows++
+			continue
+		}
+
+		valid, err := importer.validate(ctx, row, &record, v, columns)
+		if err != nil {
+			return importer.summary, err
+		}
+		if !valid {
+			importer.summary.InvalidRows++
+			continue
+		}
+
+		if err := fn(row, &record); err != nil {
+			return importer.summary, err
+		}
+		importer.summary.ValidRows++
+	}
+
+	importer.summary.Truncated = importer.hasMoreRows()
+	importer.violations = importer.violations[:importer.config.MaxErrors]
+	return importer.summary, importer.err()
+}
+
+// csvColumnCache caches the columns of the struct types decoded by ImportCSV.
+var csvColumnCache sync.Map // map[reflect.Type][]csvColumn
+
+// csvColumn is a struct field decoded from a CSV column.
+type csvColumn struct {
+	name     string
+	label    string
+	path     string // JSON path of the field, which addresses its validation violations
+	index    int
+	optional bool
+}
+
+// csvColumnsOf returns the columns of the struct type, from the fields tagged with `csv`.
+func csvColumnsOf(t reflect.Type) ([]csvColumn, error) {
+	if cached, ok := csvColumnCache.Load(t); ok {
+		return cached.([]csvColumn), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv: expected a struct, got %s", t)
+	}
+
+	var columns []csvColumn
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("csv")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		if !isCSVType(indirectType(field.Type)) {
+			return nil, fmt.Errorf("csv: field %s: unsupported type %s", field.Name, field.Type)
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		label := field.Tag.Get("custom")
+		if label == "" {
+			label = name
+		}
+		columns = append(columns, csvColumn{
+			name:     name,
+			label:    label,
+			path:     fieldName(field),
+			index:    i,
+			optional: options == "optional",
+		})
+	}
+
+	csvColumnCache.Store(t, columns)
+	return columns, nil
+}
+
+// isCSVType reports whether cells can be decoded into values of the type.
+func isCSVType(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// csvImporter holds the state of an import.
+type csvImporter struct {
+	reader     *csv.Reader
+	config     CSVConfig
+	summary    CSVSummary
+	violations []Violation
+}
+
+// newCSVImporter creates the importer of the CSV, applying the config defaults, skipping the byte order mark and
+// detecting the field separator from the header row when the config does not set it.
+func newCSVImporter(r io.Reader, config CSVConfig) (*csvImporter, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if bytes.HasPrefix(head, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+		head = head[len(utf8BOM):]
+	}
+
+	if config.Comma == 0 {
+		headerRow, _, _ := bytes.Cut(head, []byte("\n"))
+		config.Comma = ';'
+		if bytes.Count(headerRow, []byte(",")) > bytes.Count(headerRow, []byte(";")) {
+			config.Comma = ','
+		}
+	}
+	if config.DecimalSeparator == 0 {
+		config.DecimalSeparator = ','
+		if config.Comma == ',' {
+			config.DecimalSeparator = '.' // a comma cannot separate both fields and decimals
+		}
+	}
+	if len(config.DateLayouts) == 0 {
+		config.DateLayouts = []string{"02/01/2006", time.DateOnly}
+	}
+	if config.MaxErrors <= 0 {
+		config.MaxErrors = 100
+	}
+	if config.Locale == "" {
+		config.Locale = DefaultLocale
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = config.Comma
+	reader.FieldsPerRecord = -1 // rows of spreadsheets may omit their trailing empty cells
+	reader.ReuseRecord = true
+
+	return &csvImporter{reader: reader, config: config}, nil
+}
+
+// hasMoreRows reports whether rows other than blank ones remain to be read.
+func (imp *csvImporter) hasMoreRows() bool {
+	for {
+		cells, err := imp.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return false
+		}
+		if err != nil || !isBlankRecord(cells) {
+			return true
+		}
+	}
+}
+
+// matchHeader returns the index of the cell of each column in the rows, or -1 for missing optional columns,
+// reporting the missing required columns.
+func (imp *csvImporter) matchHeader(header []string, columns []csvColumn) []int {
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		indexes[i] = -1
+		for j, name := range header {
+			if strings.EqualFold(strings.TrimSpace(decodeCell(name)), column.name) {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 && !column.optional {
+			imp.report(Violation{Field: column.name, Row: 1, Tag: TagCSVMissingColumn}, column.name)
+		}
+	}
+	return indexes
+}
+
+// decode decodes the cells into the fields of the record, reporting whether all of them were valid.
+func (imp *csvImporter) decode(row int, cells []string, indexes []int, columns []csvColumn, record reflect.Value) bool {
+	valid := true
+	for i, column := range columns {
+		if indexes[i] < 0 || indexes[i] >= len(cells) {
+			continue
+		}
+		cell := decodeCell(cells[indexes[i]])
+		if kind, ok := imp.setCell(record.Field(column.index), cell); !ok {
+			imp.report(Violation{Field: column.name, Row: row, Tag: TagCSVType, Param: kind, Value: cell}, column.label)
+			valid = false
+		}
+	}
+	return valid
+}
+
+// setCell sets the field to the value of the cell, returning the kind of cell expected by the field when the
+// cell does not hold one.
+func (imp *csvImporter) setCell(field reflect.Value, cell string) (string, bool) {
+	trimmed := strings.TrimSpace(cell)
+	if field.Kind() == reflect.Pointer {
+		if trimmed == "" {
+			return "", true
+		}
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.String && trimmed == "" {
+		return "", true
+	}
+
+	if field.Type() == timeType {
+		for _, layout := range imp.config.DateLayouts {
+			if date, err := time.Parse(layout, trimmed); err == nil {
+				field.Set(reflect.ValueOf(date))
+				return "", true
+			}
+		}
+		return csvDate, false
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Bool:
+		value, ok := csvBooleans[strings.ToLower(trimmed)]
+		if !ok {
+			return csvBoolean, false
+		}
+		field.SetBool(value)
+	case reflect.Float32, reflect.Float64:
+		number, ok := imp.number(trimmed)
+		value, err := strconv.ParseFloat(number, field.Type().Bits())
+		if !ok || err != nil {
+			return csvNumber, false
+		}
+		field.SetFloat(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := imp.number(trimmed)
+		value, err := strconv.ParseInt(number, 10, field.Type().Bits())
+		if !ok || err != nil {
+			return csvInteger, false
+		}
+		field.SetInt(value)
+	default: // unsigned integers, as checked by isCSVType
+		number, ok := imp.number(trimmed)
+		value, err := strconv.ParseUint(number, 10, field.Type().Bits())
+		if !ok || err != nil {
+			return csvInteger, false
+		}
+		field.SetUint(value)
+	}
+	return "", true
+}
+
+// number converts a number written with the configured decimal separator, e.g. 1.234,56, to the Go syntax.
+// The thousands separator is only accepted between groups of 3 digits before the decimal separator, so that
+// 10.50 is not read as 1050 in files with comma decimals; it returns false for numbers breaking this rule.
+func (imp *csvImporter) number(s string) (string, bool) {
+	thousands := "."
+	if imp.config.DecimalSeparator == '.' {
+		thousands = ","
+	}
+
+	integer, fraction, decimal := strings.Cut(s, string(imp.config.DecimalSeparator))
+	if strings.Contains(fraction, thousands) {
+		return "", false
+	}
+	if groups := strings.Split(integer, thousands); len(groups) > 1 {
+		if lead := strings.TrimLeft(groups[0], "+-"); lead == "" || len(lead) > 3 {
+			return "", false
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return "", false
+			}
+		}
+		integer = strings.Join(groups, "")
+	}
+
+	if decimal {
+		return integer + "." + fraction, true
+	}
+	return integer, true
+}
+
+// validate normalizes and validates the record, reporting its violations at the row and their column.
+// It returns an error when normalizing fails or the validator fails with an error other than ValidationErrors.
+func (imp *csvImporter) validate(ctx context.Context, row int, record any, v Validator, columns []csvColumn) (bool, error) {
+	if err := Normalize(record); err != nil {
+		return false, err
+	}
+
+	var err error
+	switch v := v.(type) {
+	case ContextValidator:
+		err = v.StructTranslatedLocaleCtx(ctx, record, imp.config.Locale)
+	case LocalizedValidator:
+		err = v.StructTranslatedLocale(record, imp.config.Locale)
+	default:
+		err = v.StructTranslated(record)
+	}
+	if err == nil {
+		return true, nil
+	}
+
+	var validationErrs *ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return false, err
+	}
+	for _, violation := range validationErrs.Violations() {
+		violation.Row = row
+		for _, column := range columns {
+			if violation.Field == column.path {
+				violation.Field = column.name
+				break
+			}
+		}
+		violation.Message = imp.rowPrefix(row) + violation.Message
+		imp.violations = append(imp.violations, violation)
+	}
+	return false, nil
+}
+
+// report adds the violation of the CSV importer, translating its message with the label of its column.
+func (imp *csvImporter) report(violation Violation, label string) {
+	message := localizedMessage(csvMessages[violation.Tag], imp.config.Locale)
+	if names, ok := csvKindNames[violation.Param]; ok {
+		message = strings.ReplaceAll(message, "{1}", localizedMessage(names, imp.config.Locale))
+	}
+	violation.Message = imp.rowPrefix(violation.Row) + strings.ReplaceAll(message, "{0}", label)
+	imp.violations = append(imp.violations, violation)
+}
+
+// rowPrefix returns the prefix of the messages of the violations of the row.
+func (imp *csvImporter) rowPrefix(row int) string {
+	return strings.ReplaceAll(localizedMessage(csvRowPrefixes, imp.config.Locale), "{0}", strconv.Itoa(row))
+}
+
+// err returns the import-validation HTTPError with the violations and the summary, or nil without violations.
+func (imp *csvImporter) err() error {
+	if len(imp.violations) == 0 {
+		return nil
+	}
+
+	validationErrs := NewValidationErrors(CodeImportValidation.Message(imp.config.Locale))
+	validationErrs.AddViolations(imp.violations...)
+	return CodeImportValidation.New(validationErrs).
+		WithMetadata("rows", imp.summary.Rows).
+		WithMetadata("valid_rows", imp.summary.ValidRows).
+		WithMetadata("invalid_rows", imp.summary.InvalidRows).
+		WithMetadata("truncated", imp.summary.Truncated)
+}
+
+// decodeCell returns the cell, decoded from Windows-1252 when it is not valid UTF-8.
+func decodeCell(cell string) string {
+	if utf8.ValidString(cell) {
+		return cell
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().String(cell)
+	if err != nil {
+		return cell
+	}
+	return decoded
+}
+
+// isBlankRecord reports whether all cells of the record are blank, as the trailing rows of spreadsheets often are.
+func isBlankRecord(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,235 @@
+package kit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type beneficiaryRow struct {
+	Name      string     `csv:"nome" validate:"required" normalize:"trim,collapse" custom:"Nome"`
+	CPF       string     `csv:"cpf" validate:"required,cpf" normalize:"digits" custom:"CPF"`
+	BirthDate time.Time  `csv:"data_nascimento" validate:"required" custom:"Data de nascimento"`
+	Fee       float64    `csv:"mensalidade" validate:"gt=0"`
+	Holder    bool       `csv:"titular"`
+	Email     *string    `csv:"email,optional" validate:"omitempty,email"`
+	EndsAt    *time.Time `csv:"fim_vigencia,optional"`
+	Internal  string
+}
+
+func TestImportCSV(t *testing.T) {
+	birthDate := time.Date(1990, 3, 15, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)
+	email := "maria@example.com"
+
+	tests := []struct {
+		name               string
+		csv                string
+		config             kit.CSVConfig
+		expectedRows       []int
+		expectedRecords    []beneficiaryRow
+		expectedSummary    kit.CSVSummary
+		expectedViolations []kit.Violation
+	}{
+		{
+			name: "Valid rows with pt-BR conventions",
+			csv: "\ufeffNome;CPF;Data_Nascimento;Mensalidade;Titular;Email;Extra\n" +
+				" Maria   da Silva ;529.982.247-25;15/03/1990;1.234,56;Sim;maria@example.com;x\n" +
+				";;;;;;\n" +
+				"\"João; Filho\";52998224725;1990-03-15;10;não;;\n",
+			expectedRows: []int{2, 4},
+			expectedRecords: []beneficiaryRow{
+				{Name: "Maria da Silva", CPF: "52998224725", BirthDate: birthDate, Fee: 1234.56, Holder: true, Email: &email},
+				{Name: "João; Filho", CPF: "52998224725", BirthDate: birthDate, Fee: 10},
+			},
+			expectedSummary: kit.CSVSummary{Rows: 2, ValidRows: 2},
+		},
+		{
+			name: "Comma separator and dot decimals",
+			csv: "nome,cpf,data_nascimento,mensalidade,titular\n" +
+				"Maria,52998224725,1990-03-15,\"1,234.56\",1\n",
+			config:       kit.CSVConfig{DecimalSeparator: '.', DateLayouts: []string{time.DateOnly}},
+			expectedRows: []int{2},
+			expectedRecords: []beneficiaryRow{
+				{Name: "Maria", CPF: "52998224725", BirthDate: birthDate, Fee: 1234.56, Holder: true},
+			},
+			expectedSummary: kit.CSVSummary{Rows: 1, ValidRows: 1},
+		},
+		{
+			name: "Detected comma separator defaults to dot decimals",
+			csv: "nome,cpf,data_nascimento,mensalidade,titular\n" +
+				"Maria,52998224725,1990-03-15,12.50,1\n",
+			expectedRows: []int{2},
+			expectedRecords: []beneficiaryRow{
+				{Name: "Maria", CPF: "52998224725", BirthDate: birthDate, Fee: 12.5, Holder: true},
+			},
+			expectedSummary: kit.CSVSummary{Rows: 1, ValidRows: 1},
+		},
+		{
+			name: "Thousands separators out of 3-digit groups",
+			csv: "nome;cpf;data_nascimento;mensalidade;titular\n" +
+				"Maria;52998224725;15/03/1990;10.50;sim\n" +
+				"Maria;52998224725;15/03/1990;12.3.4;sim\n" +
+				"Maria;52998224725;15/03/1990;1,234.5;sim\n" +
+				"Maria;52998224725;15/03/1990;1.234.567,8;sim\n",
+			expectedRows: []int{5},
+			expectedRecords: []beneficiaryRow{
+				{Name: "Maria", CPF: "52998224725", BirthDate: birthDate, Fee: 1234567.8, Holder: true},
+			},
+			expectedSummary: kit.CSVSummary{Rows: 4, ValidRows: 1, InvalidRows: 3},
+			expectedViolations: []kit.Violation{
+				{Field: "mensalidade", Row: 2, Tag: kit.TagCSVType, Param: "number", Value: "10.50",
+					Message: "Linha 2: mensalidade deve ser um número"},
+				{Field: "mensalidade", Row: 3, Tag: kit.TagCSVType, Param: "number", Value: "12.3.4",
+					Message: "Linha 3: mensalidade deve ser um número"},
+				{Field: "mensalidade", Row: 4, Tag: kit.TagCSVType, Param: "number", Value: "1,234.5",
+					Message: "Linha 4: mensalidade deve ser um número"},
+			},
+		},
+		{
+			name:         "Windows-1252 cells",
+			csv:          "nome;cpf;data_nascimento;mensalidade;titular\n" + "Jo\xe3o;52998224725;15/03/1990;10;n\n",
+			expectedRows: []int{2},
+			expectedRecords: []beneficiaryRow{
+				{Name: "João", CPF: "52998224725", BirthDate: birthDate, Fee: 10},
+			},
+			expectedSummary: kit.CSVSummary{Rows: 1, ValidRows: 1},
+		},
+		{
+			name: "Invalid rows",
+			csv: "nome;cpf;data_nascimento;mensalidade;titular;fim_vigencia\n" +
+				"Maria;52998224725;31/02/1990;dez;talvez;01/01/2030\n" +
+				";11111111111;15/03/1990;0;sim\n" +
+				"Jo\"ão;52998224725;15/03/1990;10;sim\n" +
+				"José;52998224725;15/03/1990;10;sim;31/12/2030\n",
+			expectedRows: []int{5},
+			expectedRecords: []beneficiaryRow{
+				{Name: "José", CPF: "52998224725", BirthDate: birthDate, Fee: 10, Holder: true, EndsAt: &endsAt},
+			},
+			expectedSummary: kit.CSVSummary{Rows: 4, ValidRows: 1, InvalidRows: 3},
+			expectedViolations: []kit.Violation{
+				{Field: "data_nascimento", Row: 2, Tag: kit.TagCSVType, Param: "date", Value: "31/02/1990",
+					Message: "Linha 2: Data de nascimento deve ser uma data"},
+				{Field: "mensalidade", Row: 2, Tag: kit.TagCSVType, Param: "number", Value: "dez",
+					Message: "Linha 2: mensalidade deve ser um número"},
+				{Field: "titular", Row: 2, Tag: kit.TagCSVType, Param: "boolean", Value: "talvez",
+					Message: "Linha 2: titular deve ser sim ou não"},
+				{Field: "nome", Row: 3, Tag: "required", Value: "", Message: "Linha 3: Nome é um campo obrigatório"},
+				{Field: "cpf", Row: 3, Tag: "cpf", Value: "11111111111", Message: "Linha 3: CPF deve ser um CPF válido"},
+				{Field: "mensalidade", Row: 3, Tag: "gt", Param: "0", Value: float64(0),
+					Message: "Linha 3: mensalidade deve ser maior do que 0"},
+				{Row: 4, Tag: kit.TagCSVSyntax, Message: "Linha 4: registro malformado"},
+			},
+		},
+		{
+			name:   "Missing columns in English",
+			csv:    "nome;mensalidade\nMaria;10\n",
+			config: kit.CSVConfig{Locale: kit.LocaleEn},
+			expectedViolations: []kit.Violation{
+				{Field: "cpf", Row: 1, Tag: kit.TagCSVMissingColumn, Message: "Row 1: missing column cpf"},
+				{Field: "data_nascimento", Row: 1, Tag: kit.TagCSVMissingColumn, Message: "Row 1: missing column data_nascimento"},
+				{Field: "titular", Row: 1, Tag: kit.TagCSVMissingColumn, Message: "Row 1: missing column titular"},
+			},
+		},
+		{
+			name: "Max errors in Spanish",
+			csv: "nome;cpf;data_nascimento;mensalidade;titular\n" +
+				"Maria;52998224725;15/03/1990;1,5x;sim\n" +
+				"Maria;52998224725;15/03/1990;2,5x;sim\n" +
+				"Maria;52998224725;15/03/1990;3,5x;sim\n",
+			config:          kit.CSVConfig{MaxErrors: 2, Locale: kit.LocaleEs},
+			expectedSummary: kit.CSVSummary{Rows: 2, InvalidRows: 2, Truncated: true},
+			expectedViolations: []kit.Violation{
+				{Field: "mensalidade", Row: 2, Tag: kit.TagCSVType, Param: "number", Value: "1,5x",
+					Message: "Fila 2: mensalidade debe ser un número"},
+				{Field: "mensalidade", Row: 3, Tag: kit.TagCSVType, Param: "number", Value: "2,5x",
+					Message: "Fila 3: mensalidade debe ser un número"},
+			},
+		},
+		{
+			name: "Max errors at the last row",
+			csv: "nome;cpf;data_nascimento;mensalidade;titular\n" +
+				"Maria;52998224725;15/03/1990;1,5x;sim\n" +
+				"Maria;52998224725;15/03/1990;2,5x;sim\n" +
+				";;;;\n",
+			config:          kit.CSVConfig{MaxErrors: 2},
+			expectedSummary: kit.CSVSummary{Rows: 2, InvalidRows: 2},
+			expectedViolations: []kit.Violation{
+				{Field: "mensalidade", Row: 2, Tag: kit.TagCSVType, Param: "number", Value: "1,5x",
+					Message: "Linha 2: mensalidade deve ser um número"},
+				{Field: "mensalidade", Row: 3, Tag: kit.TagCSVType, Param: "number", Value: "2,5x",
+					Message: "Linha 3: mensalidade deve ser um número"},
+			},
+		},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []int
+			var records []beneficiaryRow
+			summary, err := kit.ImportCSV(context.Background(), strings.NewReader(tt.csv), v, tt.config,
+				func(row int, record *beneficiaryRow) error {
+					rows = append(rows, row)
+					records = append(records, *record)
+					return nil
+				})
+
+			assert.Equal(t, tt.expectedSummary, summary)
+			assert.Equal(t, tt.expectedRows, rows)
+			assert.Equal(t, tt.expectedRecords, records)
+
+			if tt.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, http.StatusUnprocessableEntity, httpError.Status)
+			assert.Equal(t, "import-validation", httpError.Slug)
+			assert.Equal(t, summary.Truncated, httpError.Metadata["truncated"])
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedViolations, validationErr.Violations())
+		})
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	v := kit.NewValidator()
+	noop := func(int, *beneficiaryRow) error { return nil }
+
+	_, err := kit.ImportCSV(context.Background(), strings.NewReader(""), v, kit.CSVConfig{}, noop)
+	var httpError *kit.HTTPError
+	require.ErrorAs(t, err, &httpError)
+	assert.Equal(t, "bad-input", httpError.Slug)
+
+	errStop := errors.New("database unavailable")
+	summary, err := kit.ImportCSV(context.Background(),
+		strings.NewReader("nome;cpf;data_nascimento;mensalidade;titular\nMaria;52998224725;15/03/1990;10;sim\n"),
+		v, kit.CSVConfig{}, func(int, *beneficiaryRow) error { return errStop })
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, kit.CSVSummary{Rows: 1}, summary)
+
+	_, err = kit.ImportCSV(context.Background(), strings.NewReader("tags\n"), v, kit.CSVConfig{},
+		func(int, *struct {
+			Tags []string `csv:"tags"`
+		}) error {
+			return nil
+		})
+	assert.EqualError(t, err, "csv: field Tags: unsupported type []string")
+
+	_, err = kit.ImportCSV(context.Background(), strings.NewReader("value\n"), v, kit.CSVConfig{},
+		func(int, *string) error { return nil })
+	assert.EqualError(t, err, "csv: expected a struct, got string")
+}
@@ -5,7 +5,7 @@
 // # Key Features:
 //
 //   - Validation: Provides a wrapper around `go-playground/validator`, supporting custom validation tags
-//     and localized error messages in Portuguese.
+//     and localized error messages in Portuguese, English and Spanish, negotiated from `Accept-Language`.
 //
 //   - Logging: Leverages `slog` for structured, JSON-based logging with support for contextual attributes
 //     like request ID, user details, and response status. Includes `LoggerMiddleware` for request-based logging.
@@ -0,0 +1,243 @@
+// Package kit provides utilities for structured error handling and API response formatting.
+// This file defines the error catalog, a registry where each service declares its error codes once,
+// with their slug, HTTP status, default localized messages and retryability.
+
+package kit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Built-in error codes used by kit itself.
+var (
+	CodeUnknownError = ErrorCode{
+		Slug:   "unknown-error",
+		Status: http.StatusInternalServerError,
+		Messages: map[string]string{
+			LocalePtBR: "erro desconhecido",
+			LocaleEn:   "unknown error",
+			LocaleEs:   "error desconocido",
+		},
+	}
+	CodeUnauthorized = ErrorCode{
+		Slug:   "unauthorized",
+		Status: http.StatusUnauthorized,
+		Messages: map[string]string{
+			LocalePtBR: "não autorizado",
+			LocaleEn:   "unauthorized",
+			LocaleEs:   "no autorizado",
+		},
+	}
+	CodeBadInput = ErrorCode{
+		Slug:   "bad-input",
+		Status: http.StatusBadRequest,
+		Messages: map[string]string{
+			LocalePtBR: "corpo da requisição inválido",
+			LocaleEn:   "invalid request body",
+			LocaleEs:   "cuerpo de la solicitud inválido",
+		},
+	}
+	CodeRequestValidation = ErrorCode{
+		Slug:   "request-validation",
+		Status: http.StatusBadRequest,
+		Messages: map[string]string{
+			LocalePtBR: "falha na validação",
+			LocaleEn:   "validation failed",
+			LocaleEs:   "la validación falló",
+		},
+	}
+	CodeMethodNotAllowed = ErrorCode{
+		Slug:   "method-not-allowed",
+		Status: http.StatusMethodNotAllowed,
+		Messages: map[string]string{
+			LocalePtBR: "método não permitido",
+			LocaleEn:   "method not allowed",
+			LocaleEs:   "método no permitido",
+		},
+	}
+	CodeRequestTimeout = ErrorCode{
+		Slug:   "request-timeout",
+		Status: http.StatusRequestTimeout,
+		Messages: map[string]string{
+			LocalePtBR: "tempo limite da requisição excedido",
+			LocaleEn:   "request timeout",
+			LocaleEs:   "tiempo de espera de la solicitud agotado",
+		},
+		Retryable: true,
+	}
+	CodeUnsupportedMediaType = ErrorCode{
+		Slug:   "unsupported-media-type",
+		Status: http.StatusUnsupportedMediaType,
+		Messages: map[string]string{
+			LocalePtBR: "tipo de mídia não suportado",
+			LocaleEn:   "unsupported media type",
+			LocaleEs:   "tipo de medio no soportado",
+		},
+	}
+	CodeTooManyRequests = ErrorCode{
+		Slug:   "too-many-requests",
+		Status: http.StatusTooManyRequests,
+		Messages: map[string]string{
+			LocalePtBR: "muitas requisições",
+			LocaleEn:   "too many requests",
+			LocaleEs:   "demasiadas solicitudes",
+		},
+		Retryable: true,
+	}
+	CodeServiceUnavailable = ErrorCode{
+		Slug:   "service-unavailable",
+		Status: http.StatusServiceUnavailable,
+		Messages: map[string]string{
+			LocalePtBR: "serviço indisponível",
+			LocaleEn:   "service unavailable",
+			LocaleEs:   "servicio no disponible",
+		},
+		Retryable: true,
+	}
+)
+
+// DefaultErrorCatalog is the process-wide catalog, pre-populated with the kit built-in error codes.
+var DefaultErrorCatalog = NewErrorCatalog()
+
+func init() {
+	DefaultErrorCatalog.MustRegister(
+		CodeUnknownError,
+		CodeUnauthorized,
+		CodeBadInput,
+		CodeRequestValidation,
+		CodeMethodNotAllowed,
+		CodeRequestTimeout,
+		CodeUnsupportedMediaType,
+		CodeTooManyRequests,
+		CodeServiceUnavailable,
+	)
+}
+
+// ErrorCode declares an application error: its slug, HTTP status, default messages per locale and retryability.
+type ErrorCode struct {
+	Slug      string            `json:"code"`
+	Status    int               `json:"status_code"`
+	Messages  map[string]string `json:"messages"`
+	Retryable bool              `json:"retryable"`
+}
+
+// Message returns the message of the error code for the given locale,
+// falling back to the DefaultLocale message when the locale is not declared.
+func (c ErrorCode) Message(locale string) string {
+	return localizedMessage(c.Messages, locale)
+}
+
+// New creates an HTTPError from the error code. When err is nil, the DefaultLocale message is used,
+// and ErrorHandler replaces it with the message of the request locale.
+func (c ErrorCode) New(err error) *HTTPError {
+	if err != nil {
+		return NewHTTPError(c.Status, c.Slug, err)
+	}
+
+	e := NewHTTPError(c.Status, c.Slug, errors.New(c.Message(DefaultLocale)))
+	e.cause = nil
+	e.messages = c.Messages
+	return e
+}
+
+// ErrorCatalog is a concurrency-safe registry of error codes indexed by slug.
+type ErrorCatalog struct {
+	mu    sync.RWMutex
+	codes map[string]ErrorCode
+}
+
+// NewErrorCatalog creates an empty ErrorCatalog.
+func NewErrorCatalog() *ErrorCatalog {
+	return &ErrorCatalog{codes: map[string]ErrorCode{}}
+}
+
+// Register adds the error codes to the catalog. Registering a slug that already exists with a
+// different definition returns an error, so conflicting declarations are detected at startup.
+// Registering the exact same definition twice is a no-op.
+func (c *ErrorCatalog) Register(codes ...ErrorCode) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, code := range codes {
+		if code.Slug == "" || code.Status == 0 {
+			errs = append(errs, fmt.Errorf("error code %q: slug and status are required", code.Slug))
+			continue
+		}
+
+		if registered, found := c.codes[code.Slug]; found {
+			if !reflect.DeepEqual(registered, code) {
+				errs = append(errs, fmt.Errorf("error code %q: already registered with status %d", code.Slug, registered.Status))
+			}
+			continue
+		}
+
+		c.codes[code.Slug] = code
+	}
+
+	return errors.Join(errs...)
+}
+
+// MustRegister is like Register but panics if any error code conflicts with a registered one.
+func (c *ErrorCatalog) MustRegister(codes ...ErrorCode) {
+	if err := c.Register(codes...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the error code registered with the given slug.
+func (c *ErrorCatalog) Lookup(slug string) (ErrorCode, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	code, found := c.codes[slug]
+	return code, found
+}
+
+// New creates an HTTPError from the error code registered with the given slug.
+// Unregistered slugs produce an unknown-error, since they indicate a programming error.
+func (c *ErrorCatalog) New(slug string, err error) *HTTPError {
+	code, found := c.Lookup(slug)
+	if !found {
+		if err == nil {
+			return HTTPInternalServerError(fmt.Errorf("error code %q is not registered", slug))
+		}
+		return HTTPInternalServerError(fmt.Errorf("error code %q is not registered: %w", slug, err))
+	}
+	return code.New(err)
+}
+
+// Codes returns all registered error codes sorted by slug.
+func (c *ErrorCatalog) Codes() []ErrorCode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	codes := make([]ErrorCode, 0, len(c.codes))
+	for _, code := range c.codes {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Slug < codes[j].Slug })
+	return codes
+}
+
+// MarshalJSON exports the full catalog as a JSON array of error codes sorted by slug.
+func (c *ErrorCatalog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Codes())
+}
+
+// RegisterErrorCodes adds the error codes to the DefaultErrorCatalog.
+func RegisterErrorCodes(codes ...ErrorCode) error {
+	return DefaultErrorCatalog.Register(codes...)
+}
+
+// MustRegisterErrorCodes adds the error codes to the DefaultErrorCatalog, panicking on conflicts.
+func MustRegisterErrorCodes(codes ...ErrorCode) {
+	DefaultErrorCatalog.MustRegister(codes...)
+}
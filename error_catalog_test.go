@@ -0,0 +1,142 @@
+package kit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var codeUserNotFound = kit.ErrorCode{
+	Slug:   "user-not-found",
+	Status: http.StatusNotFound,
+	Messages: map[string]string{
+		kit.LocalePtBR: "usuário não encontrado",
+		kit.LocaleEn:   "user not found",
+	},
+}
+
+func TestErrorCatalogRegister(t *testing.T) {
+	tests := []struct {
+		name        string
+		codes       []kit.ErrorCode
+		expectError bool
+	}{
+		{
+			name:        "Register new code",
+			codes:       []kit.ErrorCode{codeUserNotFound},
+			expectError: false,
+		},
+		{
+			name:        "Register the same definition twice",
+			codes:       []kit.ErrorCode{codeUserNotFound, codeUserNotFound},
+			expectError: false,
+		},
+		{
+			name:        "Register the same slug with a different status",
+			codes:       []kit.ErrorCode{codeUserNotFound, {Slug: "user-not-found", Status: http.StatusGone}},
+			expectError: true,
+		},
+		{
+			name:        "Register a code without status",
+			codes:       []kit.ErrorCode{{Slug: "no-status"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := kit.NewErrorCatalog()
+			err := catalog.Register(tt.codes...)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Panics(t, func() { kit.NewErrorCatalog().MustRegister(tt.codes...) })
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestErrorCatalogNew(t *testing.T) {
+	catalog := kit.NewErrorCatalog()
+	catalog.MustRegister(codeUserNotFound)
+
+	tests := []struct {
+		name            string
+		slug            string
+		err             error
+		expectedSlug    string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "Registered code with error",
+			slug:            "user-not-found",
+			err:             errors.New("user 10 not found"),
+			expectedSlug:    "user-not-found",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "user 10 not found",
+		},
+		{
+			name:            "Registered code without error uses the default message",
+			slug:            "user-not-found",
+			err:             nil,
+			expectedSlug:    "user-not-found",
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "usuário não encontrado",
+		},
+		{
+			name:            "Unregistered code",
+			slug:            "missing-code",
+			err:             nil,
+			expectedSlug:    "unknown-error",
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: `error code "missing-code" is not registered`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpError := catalog.New(tt.slug, tt.err)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug, "Slug should match")
+			assert.Equal(t, tt.expectedStatus, httpError.Status, "HTTP status should match")
+			assert.Equal(t, tt.expectedMessage, httpError.Message, "Error message should match")
+		})
+	}
+}
+
+func TestErrorCodeMessage(t *testing.T) {
+	assert.Equal(t, "user not found", codeUserNotFound.Message(kit.LocaleEn))
+	assert.Equal(t, "usuário não encontrado", codeUserNotFound.Message("es"), "Unknown locales fall back to the default locale")
+}
+
+func TestErrorCatalogExport(t *testing.T) {
+	catalog := kit.NewErrorCatalog()
+	catalog.MustRegister(codeUserNotFound, kit.CodeBadInput)
+
+	data, err := json.Marshal(catalog)
+	require.NoError(t, err)
+
+	var exported []map[string]any
+	require.NoError(t, json.Unmarshal(data, &exported))
+	require.Len(t, exported, 2)
+	assert.Equal(t, "bad-input", exported[0]["code"])
+	assert.Equal(t, "user-not-found", exported[1]["code"])
+	assert.Equal(t, float64(http.StatusNotFound), exported[1]["status_code"])
+	assert.Equal(t, false, exported[1]["retryable"])
+}
+
+func TestDefaultErrorCatalog(t *testing.T) {
+	code, found := kit.DefaultErrorCatalog.Lookup("request-validation")
+	assert.True(t, found)
+	assert.Equal(t, kit.CodeRequestValidation, code)
+
+	assert.NoError(t, kit.RegisterErrorCodes(kit.CodeBadInput), "Re-registering a built-in code is a no-op")
+	assert.Error(t, kit.RegisterErrorCodes(kit.ErrorCode{Slug: "bad-input", Status: http.StatusConflict}))
+	assert.Panics(t, func() { kit.MustRegisterErrorCodes(kit.ErrorCode{Slug: "bad-input", Status: http.StatusConflict}) })
+}
@@ -11,32 +11,226 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// ErrorFormat defines how ErrorHandler renders the error response body.
+type ErrorFormat int
+
+const (
+	// ErrorFormatNegotiate renders a problem details document when the client asks for
+	// application/problem+json in the Accept header and the legacy format otherwise.
+	ErrorFormatNegotiate ErrorFormat = iota
+	// ErrorFormatLegacy always renders the legacy `{"error": HTTPError}` format.
+	ErrorFormatLegacy
+	// ErrorFormatProblem always renders an RFC 9457 problem details document.
+	ErrorFormatProblem
+)
+
+// ErrorExposure defines how much of an internal error ErrorHandler exposes to the client.
+type ErrorExposure int
+
+const (
+	// ExposureProduction replaces the message of 5xx responses with a generic localized message.
+	// The real message only goes to the logs, correlated by the request ID returned to the client.
+	ExposureProduction ErrorExposure = iota
+	// ExposureDebug returns the real message of every response along with the cause chain and
+	// the captured stack trace. Intended for local development only.
+	ExposureDebug
+)
+
+// DefaultProblemTypeBaseURI is the prefix used to build the problem details type URI from the error slug.
+const DefaultProblemTypeBaseURI = "urn:problem-type:"
+
+// DefaultMaskedMessages are the generic messages, per locale, returned in place of the message of 5xx responses.
+var DefaultMaskedMessages = map[string]string{
+	LocalePtBR: "erro interno do servidor",
+	LocaleEn:   "internal server error",
+	LocaleEs:   "error interno del servidor",
+}
+
+// ErrorHandlerConfig defines the configuration for the ErrorHandler.
+type ErrorHandlerConfig struct {
+	// Format selects the response body format. Defaults to ErrorFormatNegotiate.
+	Format ErrorFormat
+	// ProblemTypeBaseURI is prepended to the slug to build the problem details type URI.
+	// Defaults to DefaultProblemTypeBaseURI.
+	ProblemTypeBaseURI string
+	// StringDetails renders the validation details as plain message strings instead of structured
+	// violations, in both formats, for clients that predate field-addressable details.
+	StringDetails bool
+	// Exposure selects how much of internal errors is returned to the client. Defaults to ExposureProduction,
+	// which also omits the rejected values of the violations, as they may hold personal data such as CPFs.
+	Exposure ErrorExposure
+	// MaskedMessages are the generic messages, per locale, of 5xx responses in production.
+	// Defaults to DefaultMaskedMessages.
+	MaskedMessages map[string]string
+	// Mappers convert errors that are not an HTTPError, in order, before DefaultErrorMappers
+	// and the fallback to an unknown-error.
+	Mappers []ErrorMapper
+}
+
+// ErrorDebug carries the cause chain and the stack trace of an error, returned only in debug exposure.
+type ErrorDebug struct {
+	Chain []string `json:"chain,omitempty"`
+	Stack []string `json:"stack,omitempty"`
+}
+
+// problemResponse is the problem details document, whose details may be plain message strings in compatibility mode.
+type problemResponse struct {
+	*ProblemDetails
+	Details any `json:"details,omitempty"`
+}
+
+// errorResponse is the legacy representation of an HTTPError, extended with the request ID and debug data.
+// Details holds either the structured violations or, in compatibility mode, plain message strings.
+type errorResponse struct {
+	*HTTPError
+	Details   any         `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+	Debug     *ErrorDebug `json:"debug,omitempty"`
+}
+
 // ErrorHandler returns a Fiber-compatible error handler that maps errors to structured JSON responses.
-// If the error is not a fiber.Error or HTTPError, it wraps it in a generic unknown-error with HTTP 500 status.
+// If the error chain holds no HTTPError, no DefaultErrorMappers handles it and it is not a fiber.Error,
+// it wraps it in a generic unknown-error with HTTP 500 status.
 func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
+	return ErrorHandlerWithConfig(logger, ErrorHandlerConfig{
+		Format:             ErrorFormatNegotiate,
+		ProblemTypeBaseURI: DefaultProblemTypeBaseURI,
+	})
+}
+
+// ErrorHandlerWithConfig returns a Fiber-compatible error handler configured by the given ErrorHandlerConfig.
+// Clients negotiating application/problem+json receive an RFC 9457 document, others the legacy format.
+func ErrorHandlerWithConfig(logger *slog.Logger, config ErrorHandlerConfig) fiber.ErrorHandler {
+	if config.ProblemTypeBaseURI == "" {
+		config.ProblemTypeBaseURI = DefaultProblemTypeBaseURI
+	}
+	if config.MaskedMessages == nil {
+		config.MaskedMessages = DefaultMaskedMessages
+	}
+
 	return func(c *fiber.Ctx, err error) error {
 
+		// An HTTPError in the chain wins over the fiber.Error it may wrap, such as the one of BodyParser,
+		// and the mappers over the fiber.Error they may handle.
+		var e *HTTPError
 		var fiberErr *fiber.Error
-		if errors.As(err, &fiberErr) {
+		if !errors.As(err, &e) {
+			e = mapError(err, config.Mappers, DefaultErrorMappers)
+		}
+
+		if e == nil && errors.As(err, &fiberErr) {
 			logFiberError(logger, c, fiberErr)
-			return c.Status(fiberErr.Code).JSON(Map{
-				"error": HTTPError{
-					Slug:    "fiber-err",
-					Message: fiberErr.Message + ": " + fiberErr.Error(),
-					Status:  fiberErr.Code,
-				},
+			e = &HTTPError{
+				Slug:    "fiber-err",
+				Message: fiberErr.Message + ": " + fiberErr.Error(),
+				Status:  fiberErr.Code,
+			}
+		} else {
+			if e == nil {
+				e = HTTPInternalServerError(err)
+			}
+			// LoggerMiddleware logs the error with the request record when it is in the chain.
+			if c.Locals(CtxKeyLogger) == nil {
+				logHTTPError(logger, c, e, err)
+			}
+		}
+
+		for key, values := range e.Header {
+			for _, value := range values {
+				c.Response().Header.Add(key, value)
+			}
+		}
+
+		requestID := getContextValue(c, CtxKeyRequestID, "")
+		locale := RequestLocale(c)
+
+		// public is the copy of the error exposed to the client
+		public := *e
+		if e.messages != nil {
+			public.Message = localizedMessage(e.messages, locale)
+		}
+
+		var debug *ErrorDebug
+		if config.Exposure == ExposureDebug {
+			debug = &ErrorDebug{Chain: ErrorChain(err), Stack: e.StackTrace()}
+		} else {
+			public.Details = redactViolations(e.Details)
+			if e.Status >= http.StatusInternalServerError {
+				public.Message = localizedMessage(config.MaskedMessages, locale)
+			}
+		}
+
+		var details any
+		if len(public.Details) > 0 {
+			details = public.Details
+			if config.StringDetails {
+				details = public.DetailMessages()
+			}
+		}
+
+		if !useProblemFormat(c, config.Format) {
+			return c.Status(e.Status).JSON(Map{
+				"error": errorResponse{HTTPError: &public, Details: details, RequestID: requestID, Debug: debug},
 			})
 		}
 
-		var e *HTTPError
-		if !errors.As(err, &e) {
-			e = HTTPInternalServerError(err)
+		problem := NewProblemDetails(&public, config.ProblemTypeBaseURI, c.Path())
+		problem.RequestID = requestID
+		problem.Debug = debug
+
+		return c.Status(e.Status).JSON(problemResponse{ProblemDetails: problem, Details: details}, MIMEApplicationProblemJSON)
+	}
+}
+
+// redactViolations returns a copy of the violations without their rejected values.
+func redactViolations(violations []Violation) []Violation {
+	if len(violations) == 0 {
+		return violations
+	}
+
+	redacted := make([]Violation, len(violations))
+	for i, v := range violations {
+		v.Value = nil
+		redacted[i] = v
+	}
+	return redacted
+}
+
+// useProblemFormat reports whether the error response must be rendered as a problem details document.
+func useProblemFormat(c *fiber.Ctx, format ErrorFormat) bool {
+	switch format {
+	case ErrorFormatProblem:
+		return true
+	case ErrorFormatLegacy:
+		return false
+	default:
+		if c.Get(fiber.HeaderAccept) == "" {
+			return false
 		}
+		return c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationProblemJSON) == MIMEApplicationProblemJSON
+	}
+}
 
-		return c.Status(e.Status).JSON(Map{
-			"error": e,
-		})
+func logHTTPError(logger *slog.Logger, c *fiber.Ctx, e *HTTPError, err error) {
+	requestAttributes := []slog.Attr{
+		slog.String("method", string(c.Context().Method())),
+		slog.String("host", c.Hostname()),
+		slog.String("path", c.Path()),
 	}
+
+	level := slog.LevelWarn
+	if e.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	msg := "request failed: " + e.Message
+
+	attributes := []slog.Attr{
+		{Key: "request", Value: slog.GroupValue(requestAttributes...)},
+		errorAttr(err),
+	}
+
+	logger.LogAttrs(c.UserContext(), level, msg, attributes...)
 }
 
 func logFiberError(logger *slog.Logger, c *fiber.Ctx, fiberErr *fiber.Error) {
@@ -3,10 +3,13 @@ package kit_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/arvo-health/kit"
 	"github.com/gofiber/fiber/v2"
@@ -33,6 +36,18 @@ func TestErrorHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "HTTPError wrapping a Fiber Error keeps its own code and status",
+			inputError:     kit.HTTPBadRequestError("bad-input", fiber.ErrUnprocessableEntity),
+			expectedStatus: http.StatusBadRequest,
+			expectedErrorBody: map[string]any{
+				"error": map[string]any{
+					"code":        "bad-input",
+					"message":     "Unprocessable Entity",
+					"status_code": float64(http.StatusBadRequest),
+				},
+			},
+		},
 		{
 			name:           "ResponseError with ValidationError handled correctly",
 			inputError:     kit.HTTPUnprocessableEntityError("validation", kit.NewValidationErrors("validation error", "field1 is required")),
@@ -42,7 +57,7 @@ func TestErrorHandler(t *testing.T) {
 					"code":        "validation",
 					"status_code": float64(http.StatusUnprocessableEntity),
 					"message":     "validation error",
-					"details":     []any{"field1 is required"},
+					"details":     []any{map[string]any{"message": "field1 is required"}},
 				},
 			},
 		},
@@ -66,7 +81,7 @@ func TestErrorHandler(t *testing.T) {
 				"error": map[string]any{
 					"code":        "unknown-error",
 					"status_code": float64(http.StatusInternalServerError),
-					"message":     "internal server error",
+					"message":     "erro interno do servidor",
 				},
 			},
 		},
@@ -103,3 +118,446 @@ func TestErrorHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorHandlerProblemDetails(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              kit.ErrorHandlerConfig
+		accept              string
+		expectedContentType string
+		expectedBody        map[string]any
+	}{
+		{
+			name:                "Negotiated problem details",
+			config:              kit.ErrorHandlerConfig{},
+			accept:              "application/problem+json",
+			expectedContentType: kit.MIMEApplicationProblemJSON,
+			expectedBody: map[string]any{
+				"type":     "urn:problem-type:request-validation",
+				"title":    "Bad Request",
+				"status":   float64(http.StatusBadRequest),
+				"detail":   "validation failed",
+				"instance": "/test",
+				"code":     "request-validation",
+				"details":  []any{map[string]any{"message": "Nome é um campo obrigatório"}},
+			},
+		},
+		{
+			name:                "Forced problem details with custom type base URI",
+			config:              kit.ErrorHandlerConfig{Format: kit.ErrorFormatProblem, ProblemTypeBaseURI: "https://errors.example.com/"},
+			accept:              "",
+			expectedContentType: kit.MIMEApplicationProblemJSON,
+			expectedBody: map[string]any{
+				"type":     "https://errors.example.com/request-validation",
+				"title":    "Bad Request",
+				"status":   float64(http.StatusBadRequest),
+				"detail":   "validation failed",
+				"instance": "/test",
+				"code":     "request-validation",
+				"details":  []any{map[string]any{"message": "Nome é um campo obrigatório"}},
+			},
+		},
+		{
+			name:                "Legacy format when client accepts JSON",
+			config:              kit.ErrorHandlerConfig{},
+			accept:              "application/json",
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody: map[string]any{
+				"error": map[string]any{
+					"code":        "request-validation",
+					"message":     "validation failed",
+					"details":     []any{map[string]any{"message": "Nome é um campo obrigatório"}},
+					"status_code": float64(http.StatusBadRequest),
+				},
+			},
+		},
+		{
+			name:                "Legacy format forced even when problem details is accepted",
+			config:              kit.ErrorHandlerConfig{Format: kit.ErrorFormatLegacy},
+			accept:              "application/problem+json",
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody: map[string]any{
+				"error": map[string]any{
+					"code":        "request-validation",
+					"message":     "validation failed",
+					"details":     []any{map[string]any{"message": "Nome é um campo obrigatório"}},
+					"status_code": float64(http.StatusBadRequest),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandlerWithConfig(slog.Default(), tt.config),
+			})
+
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return kit.HTTPBadRequestError("request-validation",
+					kit.NewValidationErrors("validation failed", "Nome é um campo obrigatório"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			var respBody map[string]any
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Equal(t, tt.expectedContentType, resp.Header.Get(fiber.HeaderContentType))
+			assert.Equal(t, tt.expectedBody, respBody)
+		})
+	}
+}
+
+func TestErrorHandlerProblemDetailsRequestID(t *testing.T) {
+	logger, _ := kit.NewTestLogger()
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: kit.ErrorHandlerWithConfig(logger, kit.ErrorHandlerConfig{Format: kit.ErrorFormatProblem}),
+	})
+	app.Use(kit.LoggerMiddleware(logger))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return kit.HTTPNotFoundError("user-not-found", errors.New("user not found"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/10", nil)
+	req.Header.Set(kit.RequestIDHeaderKey, "request-id")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+	var problem kit.ProblemDetails
+	err = json.NewDecoder(resp.Body).Decode(&problem)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.Equal(t, "/users/10", problem.Instance)
+	assert.Equal(t, "request-id", problem.RequestID)
+}
+
+func TestErrorHandlerDetailsFormat(t *testing.T) {
+	validationErrs := kit.NewValidationErrors("validation failed")
+	validationErrs.AddViolations(kit.Violation{
+		Field:   "items[2].cpf",
+		Tag:     "cpf",
+		Value:   "52998224724",
+		Message: "CPF deve ser um CPF válido",
+	})
+
+	tests := []struct {
+		name            string
+		config          kit.ErrorHandlerConfig
+		expectedDetails []any
+	}{
+		{
+			name:   "Structured violations without values by default",
+			config: kit.ErrorHandlerConfig{},
+			expectedDetails: []any{map[string]any{
+				"field":   "items[2].cpf",
+				"tag":     "cpf",
+				"message": "CPF deve ser um CPF válido",
+			}},
+		},
+		{
+			name:   "Structured violations with values in debug exposure",
+			config: kit.ErrorHandlerConfig{Exposure: kit.ExposureDebug},
+			expectedDetails: []any{map[string]any{
+				"field":   "items[2].cpf",
+				"tag":     "cpf",
+				"value":   "52998224724",
+				"message": "CPF deve ser um CPF válido",
+			}},
+		},
+		{
+			name:            "Plain strings in compatibility mode",
+			config:          kit.ErrorHandlerConfig{StringDetails: true},
+			expectedDetails: []any{"CPF deve ser um CPF válido"},
+		},
+		{
+			name:            "Plain strings in compatibility mode of problem details",
+			config:          kit.ErrorHandlerConfig{Format: kit.ErrorFormatProblem, StringDetails: true},
+			expectedDetails: []any{"CPF deve ser um CPF válido"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandlerWithConfig(slog.Default(), tt.config),
+			})
+
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return kit.HTTPBadRequestError("request-validation", validationErrs)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			var respBody map[string]any
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+
+			if tt.config.Format == kit.ErrorFormatProblem {
+				assert.Equal(t, "request-validation", respBody["code"])
+				assert.Equal(t, tt.expectedDetails, respBody["details"])
+				return
+			}
+
+			body, ok := respBody["error"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "request-validation", body["code"])
+			assert.Equal(t, "validation failed", body["message"])
+			assert.Equal(t, tt.expectedDetails, body["details"])
+		})
+	}
+}
+
+func TestErrorHandlerLogsCauseChain(t *testing.T) {
+	errSentinel := errors.New("connection refused")
+
+	tests := []struct {
+		name            string
+		withMiddleware  bool
+		expectedRecords int
+	}{
+		{
+			name:            "ErrorHandler logs the error when LoggerMiddleware is absent",
+			withMiddleware:  false,
+			expectedRecords: 1,
+		},
+		{
+			name:            "LoggerMiddleware logs the error with the request record",
+			withMiddleware:  true,
+			expectedRecords: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, handler := kit.NewTestLogger()
+
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandler(logger),
+			})
+			if tt.withMiddleware {
+				app.Use(kit.LoggerMiddleware(logger))
+			}
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return kit.HTTPInternalServerError(fmt.Errorf("querying database: %w", errSentinel)).WithStack()
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.NotContains(t, string(body), "stack", "Stack trace should never be serialized")
+
+			records := handler.CapturedRecords()
+			require.Len(t, records, tt.expectedRecords)
+			assert.Equal(t, slog.LevelError, records[0].Level)
+
+			var errorAttr slog.Attr
+			records[0].Attrs(func(attr slog.Attr) bool {
+				if attr.Key == "error" {
+					errorAttr = attr
+					return false
+				}
+				return true
+			})
+
+			group := map[string]slog.Value{}
+			for _, attr := range errorAttr.Value.Group() {
+				group[attr.Key] = attr.Value
+			}
+			assert.Equal(t, "unknown-error", group["code"].String())
+			assert.Equal(t, []string{"querying database: connection refused", "connection refused"}, group["chain"].Any())
+			assert.NotEmpty(t, group["stack"].Any())
+		})
+	}
+}
+
+func TestErrorHandlerExposure(t *testing.T) {
+	internalErr := fmt.Errorf("querying users: %w", errors.New("dial tcp db-primary:5432: connection refused"))
+
+	tests := []struct {
+		name            string
+		config          kit.ErrorHandlerConfig
+		inputError      error
+		expectedStatus  int
+		expectedMessage string
+		expectDebug     bool
+	}{
+		{
+			name:            "Production masks 5xx messages",
+			config:          kit.ErrorHandlerConfig{},
+			inputError:      internalErr,
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "erro interno do servidor",
+		},
+		{
+			name:            "Production masks 5xx messages with custom messages",
+			config:          kit.ErrorHandlerConfig{MaskedMessages: map[string]string{kit.LocalePtBR: "tente novamente mais tarde"}},
+			inputError:      kit.NewHTTPError(http.StatusBadGateway, "upstream-error", internalErr),
+			expectedStatus:  http.StatusBadGateway,
+			expectedMessage: "tente novamente mais tarde",
+		},
+		{
+			name:            "Production keeps 4xx messages",
+			config:          kit.ErrorHandlerConfig{},
+			inputError:      kit.HTTPNotFoundError("user-not-found", errors.New("user not found")),
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "user not found",
+		},
+		{
+			name:            "Debug returns the real message and the cause chain",
+			config:          kit.ErrorHandlerConfig{Exposure: kit.ExposureDebug},
+			inputError:      internalErr,
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: internalErr.Error(),
+			expectDebug:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := kit.NewTestLogger()
+
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandlerWithConfig(logger, tt.config),
+			})
+			app.Use(kit.LoggerMiddleware(logger))
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return tt.inputError
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(kit.RequestIDHeaderKey, "request-id")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			var respBody struct {
+				Error struct {
+					Message   string          `json:"message"`
+					RequestID string          `json:"request_id"`
+					Debug     *kit.ErrorDebug `json:"debug"`
+				} `json:"error"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedMessage, respBody.Error.Message)
+			assert.Equal(t, "request-id", respBody.Error.RequestID)
+			if tt.expectDebug {
+				require.NotNil(t, respBody.Error.Debug)
+				assert.Equal(t, []string{
+					"querying users: dial tcp db-primary:5432: connection refused",
+					"dial tcp db-primary:5432: connection refused",
+				}, respBody.Error.Debug.Chain)
+			} else {
+				assert.Nil(t, respBody.Error.Debug)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerHeaders(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: kit.ErrorHandler(slog.Default()),
+	})
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return kit.HTTPTooManyRequestsError(30*time.Second, errors.New("rate limit exceeded")).
+			WithMetadata("limit", 100)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+	var respBody map[string]any
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+	assert.Equal(t, map[string]any{
+		"code":        "too-many-requests",
+		"message":     "rate limit exceeded",
+		"status_code": float64(http.StatusTooManyRequests),
+		"metadata":    map[string]any{"retry_after": float64(30), "limit": float64(100)},
+	}, respBody["error"])
+}
+
+func TestErrorHandlerLocale(t *testing.T) {
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		inputError      error
+		expectedMessage string
+	}{
+		{
+			name:            "Masked message in English",
+			acceptLanguage:  "en-US",
+			inputError:      errors.New("boom"),
+			expectedMessage: "internal server error",
+		},
+		{
+			name:            "Masked message in Spanish",
+			acceptLanguage:  "es",
+			inputError:      errors.New("boom"),
+			expectedMessage: "error interno del servidor",
+		},
+		{
+			name:            "Error code default message follows the locale",
+			acceptLanguage:  "en",
+			inputError:      kit.CodeTooManyRequests.New(nil),
+			expectedMessage: "too many requests",
+		},
+		{
+			name:            "Explicit messages are kept",
+			acceptLanguage:  "en",
+			inputError:      kit.CodeTooManyRequests.New(errors.New("limite excedido")),
+			expectedMessage: "limite excedido",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandler(slog.Default()),
+			})
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return tt.inputError
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(fiber.HeaderAcceptLanguage, tt.acceptLanguage)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			var respBody map[string]map[string]any
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedMessage, respBody["error"]["message"])
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package kit provides foundational utilities for structured error handling in Go applications.
+// This file defines error mappers, which convert domain and standard library errors into HTTPError
+// before ErrorHandler falls back to an unknown-error.
+
+package kit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client cancels the request.
+const StatusClientClosedRequest = 499
+
+// Built-in error codes of the default error mappers.
+var (
+	CodeTimeout = ErrorCode{
+		Slug:   "timeout",
+		Status: http.StatusGatewayTimeout,
+		Messages: map[string]string{
+			LocalePtBR: "tempo limite excedido",
+			LocaleEn:   "timeout exceeded",
+			LocaleEs:   "tiempo de espera agotado",
+		},
+		Retryable: true,
+	}
+	CodeClientClosedRequest = ErrorCode{
+		Slug:   "client-closed-request",
+		Status: StatusClientClosedRequest,
+		Messages: map[string]string{
+			LocalePtBR: "requisição cancelada pelo cliente",
+			LocaleEn:   "request canceled by the client",
+			LocaleEs:   "solicitud cancelada por el cliente",
+		},
+	}
+	CodeRequestTooLarge = ErrorCode{
+		Slug:   "request-too-large",
+		Status: http.StatusRequestEntityTooLarge,
+		Messages: map[string]string{
+			LocalePtBR: "corpo da requisição muito grande",
+			LocaleEn:   "request body too large",
+			LocaleEs:   "cuerpo de la solicitud demasiado grande",
+		},
+	}
+)
+
+func init() {
+	DefaultErrorCatalog.MustRegister(CodeTimeout, CodeClientClosedRequest, CodeRequestTooLarge)
+}
+
+// ErrorMapper converts an error into an HTTPError, returning nil when it does not handle the error.
+type ErrorMapper func(err error) *HTTPError
+
+// DefaultErrorMappers are applied by ErrorHandler after the configured mappers:
+// deadline exceeded becomes a 504, client cancellation a 499 and body too large a 413.
+var DefaultErrorMappers = []ErrorMapper{
+	MapErrorCode(context.DeadlineExceeded, CodeTimeout),
+	MapErrorCode(context.Canceled, CodeClientClosedRequest),
+	MapErrorCode(fasthttp.ErrBodyTooLarge, CodeRequestTooLarge),
+	mapMaxBytesError,
+}
+
+// MapError returns an ErrorMapper that converts errors matching target, according to errors.Is,
+// into an HTTPError with the given status and slug.
+func MapError(target error, status int, slug string) ErrorMapper {
+	return func(err error) *HTTPError {
+		if errors.Is(err, target) {
+			return NewHTTPError(status, slug, err)
+		}
+		return nil
+	}
+}
+
+// MapErrorCode returns an ErrorMapper that converts errors matching target, according to errors.Is,
+// into an HTTPError from the given error code.
+func MapErrorCode(target error, code ErrorCode) ErrorMapper {
+	return func(err error) *HTTPError {
+		if errors.Is(err, target) {
+			return code.New(err)
+		}
+		return nil
+	}
+}
+
+// mapMaxBytesError converts the error returned by http.MaxBytesReader into a request-too-large error.
+func mapMaxBytesError(err error) *HTTPError {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return CodeRequestTooLarge.New(err)
+	}
+	return nil
+}
+
+// mapError applies the mappers in order and returns the first HTTPError produced, or nil if none handles the error.
+func mapError(err error, mappers ...[]ErrorMapper) *HTTPError {
+	for _, group := range mappers {
+		for _, mapper := range group {
+			if e := mapper(err); e != nil {
+				return e
+			}
+		}
+	}
+	return nil
+}
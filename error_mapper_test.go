@@ -0,0 +1,142 @@
+package kit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+var errUserBlocked = errors.New("user blocked")
+
+func TestMapError(t *testing.T) {
+	mapper := kit.MapError(sql.ErrNoRows, http.StatusNotFound, "not-found")
+
+	httpError := mapper(fmt.Errorf("finding user: %w", sql.ErrNoRows))
+	require.NotNil(t, httpError)
+	assert.Equal(t, http.StatusNotFound, httpError.Status, "HTTP status should match")
+	assert.Equal(t, "not-found", httpError.Slug, "Slug should match")
+	assert.ErrorIs(t, httpError, sql.ErrNoRows, "The cause should be preserved")
+
+	assert.Nil(t, mapper(errors.New("other error")), "Unrelated errors should not be mapped")
+}
+
+func TestErrorHandlerMappers(t *testing.T) {
+	maxBytesReader := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader("too large")), 1)
+	_, maxBytesErr := io.ReadAll(maxBytesReader)
+
+	tests := []struct {
+		name           string
+		mappers        []kit.ErrorMapper
+		inputError     error
+		expectedStatus int
+		expectedSlug   string
+	}{
+		{
+			name:           "Configured sentinel mapping",
+			mappers:        []kit.ErrorMapper{kit.MapError(sql.ErrNoRows, http.StatusNotFound, "not-found")},
+			inputError:     fmt.Errorf("finding user: %w", sql.ErrNoRows),
+			expectedStatus: http.StatusNotFound,
+			expectedSlug:   "not-found",
+		},
+		{
+			name: "Configured mapper function",
+			mappers: []kit.ErrorMapper{func(err error) *kit.HTTPError {
+				if errors.Is(err, errUserBlocked) {
+					return kit.HTTPForbiddenError("user-blocked", err)
+				}
+				return nil
+			}},
+			inputError:     errUserBlocked,
+			expectedStatus: http.StatusForbidden,
+			expectedSlug:   "user-blocked",
+		},
+		{
+			name:           "Configured mappers take precedence over the defaults",
+			mappers:        []kit.ErrorMapper{kit.MapError(context.DeadlineExceeded, http.StatusServiceUnavailable, "unavailable")},
+			inputError:     context.DeadlineExceeded,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedSlug:   "unavailable",
+		},
+		{
+			name:           "Configured mappers take precedence over Fiber errors",
+			mappers:        []kit.ErrorMapper{kit.MapError(fiber.ErrNotFound, http.StatusNotFound, "route-not-found")},
+			inputError:     fmt.Errorf("routing: %w", fiber.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedSlug:   "route-not-found",
+		},
+		{
+			name:           "Deadline exceeded",
+			inputError:     fmt.Errorf("calling upstream: %w", context.DeadlineExceeded),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedSlug:   "timeout",
+		},
+		{
+			name:           "Client cancellation",
+			inputError:     context.Canceled,
+			expectedStatus: kit.StatusClientClosedRequest,
+			expectedSlug:   "client-closed-request",
+		},
+		{
+			name:           "Body too large",
+			inputError:     fasthttp.ErrBodyTooLarge,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedSlug:   "request-too-large",
+		},
+		{
+			name:           "Max bytes reader limit",
+			inputError:     maxBytesErr,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedSlug:   "request-too-large",
+		},
+		{
+			name:           "HTTPError is not mapped",
+			mappers:        []kit.ErrorMapper{kit.MapError(sql.ErrNoRows, http.StatusNotFound, "not-found")},
+			inputError:     kit.HTTPConflictError("user-exists", sql.ErrNoRows),
+			expectedStatus: http.StatusConflict,
+			expectedSlug:   "user-exists",
+		},
+		{
+			name:           "Unmapped error falls back to unknown-error",
+			inputError:     errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedSlug:   "unknown-error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := kit.NewTestLogger()
+
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandlerWithConfig(logger, kit.ErrorHandlerConfig{Mappers: tt.mappers}),
+			})
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return tt.inputError
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			var respBody map[string]map[string]any
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedSlug, respBody["error"]["code"])
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package kit provides utilities for structured error handling and API response formatting.
+// This file defines helpers to capture stack traces and to log the cause chain of errors.
+
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// kitFuncPrefix is the function name prefix of the kit package, used to trim kit frames from stack traces.
+const kitFuncPrefix = "github.com/arvo-health/kit."
+
+// callers captures the stack trace of the caller, skipping the frames inside the kit package
+// so that the trace starts where the error was created.
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	pcs = pcs[:n]
+
+	frames := runtime.CallersFrames(pcs)
+	skip := 0
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, kitFuncPrefix) || !more {
+			break
+		}
+		skip++
+	}
+
+	return pcs[skip:]
+}
+
+// formatStack formats the program counters as "function file:line" frames.
+func formatStack(pcs []uintptr) []string {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// ErrorChain returns the messages of the error and of every error it wraps, depth first.
+// Consecutive duplicated messages, common when a wrapper reuses the message of its cause, are collapsed.
+func ErrorChain(err error) []string {
+	var chain []string
+
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+
+		if msg := err.Error(); len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+
+		switch e := err.(type) { //nolint:errorlint // walking the chain manually
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+
+	return chain
+}
+
+// errorAttr builds the "error" log attribute with the message, cause chain and captured stack trace of the error.
+func errorAttr(err error) slog.Attr {
+	attributes := []slog.Attr{
+		slog.String("message", err.Error()),
+	}
+
+	if chain := ErrorChain(err); len(chain) > 1 {
+		attributes = append(attributes, slog.Any("chain", chain))
+	}
+
+	var e *HTTPError
+	if errors.As(err, &e) {
+		attributes = append(attributes, slog.String("code", e.Slug))
+		if stack := e.StackTrace(); stack != nil {
+			attributes = append(attributes, slog.Any("stack", stack))
+		}
+	}
+
+	return slog.Attr{Key: "error", Value: slog.GroupValue(attributes...)}
+}
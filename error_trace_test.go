@@ -0,0 +1,52 @@
+package kit_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorChain(t *testing.T) {
+	errSentinel := errors.New("connection refused")
+
+	tests := []struct {
+		name          string
+		err           error
+		expectedChain []string
+	}{
+		{
+			name:          "Nil error",
+			err:           nil,
+			expectedChain: nil,
+		},
+		{
+			name:          "Single error",
+			err:           errSentinel,
+			expectedChain: []string{"connection refused"},
+		},
+		{
+			name:          "Wrapped errors",
+			err:           fmt.Errorf("finding user: %w", fmt.Errorf("querying database: %w", errSentinel)),
+			expectedChain: []string{"finding user: querying database: connection refused", "querying database: connection refused", "connection refused"},
+		},
+		{
+			name:          "HTTPError reusing the message of its cause",
+			err:           kit.HTTPInternalServerError(fmt.Errorf("querying database: %w", errSentinel)),
+			expectedChain: []string{"querying database: connection refused", "connection refused"},
+		},
+		{
+			name:          "Joined errors",
+			err:           errors.Join(errors.New("first"), errors.New("second")),
+			expectedChain: []string{"first\nsecond", "first", "second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedChain, kit.ErrorChain(tt.err))
+		})
+	}
+}
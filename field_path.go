@@ -0,0 +1,240 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file resolves the fields of the paths reported by the validator, and their readable paths composed
+// of the `custom` labels (e.g. Beneficiário 4 › Documento).
+
+package kit
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// LabelPathSeparator separates the labels of a path composed of `custom` labels.
+const LabelPathSeparator = " › "
+
+// Request sources a field is bound from, reported in the Source of its violations.
+const (
+	SourceBody   = "body"
+	SourceQuery  = "query"
+	SourcePath   = "path"
+	SourceHeader = "header"
+)
+
+// sourceTags are the fiber binding tags of the request sources other than the body.
+var sourceTags = []struct {
+	tag    string
+	source string
+}{
+	{tag: "query", source: SourceQuery},
+	{tag: "params", source: SourcePath},
+	{tag: "reqHeader", source: SourceHeader},
+}
+
+// pathSegment is a field of a path, with its name, its `custom` label and the indexes that follow it.
+type pathSegment struct {
+	name    string   // JSON name, empty for embedded structs flattened into their parent
+	label   string   // `custom` label, empty when not set
+	source  string   // request source of the field, empty when bound from the body
+	indexes []string // slice, array or map indexes, without brackets
+}
+
+// labelPath converts a validator struct namespace into a readable path composed of the `custom` labels
+// of its fields, with 1-based indexes (e.g. Beneficiário 4 › Documento). Fields without a label keep their
+// JSON name and indexes. When the field itself has no label, its JSON path is returned.
+func labelPath(root reflect.Type, structNamespace string) string {
+	segments := fieldPath(root, structNamespace)
+	if len(segments) == 0 || segments[len(segments)-1].label == "" {
+		return jsonPath(root, structNamespace)
+	}
+
+	labels := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		var label strings.Builder
+		if segment.label == "" {
+			label.WriteString(segment.name)
+			for _, index := range segment.indexes {
+				label.WriteString("[" + index + "]")
+			}
+		} else {
+			label.WriteString(segment.label)
+			for _, index := range segment.indexes {
+				label.WriteByte(' ')
+				if n, err := strconv.Atoi(index); err == nil {
+					index = strconv.Itoa(n + 1)
+				}
+				label.WriteString(index)
+			}
+		}
+		labels = append(labels, label.String())
+	}
+	return strings.Join(labels, LabelPathSeparator)
+}
+
+// fieldPath resolves the segments of a validator struct namespace, following the fields of the root type.
+// Embedded structs without a JSON name are omitted. It returns nil if the namespace has no field.
+func fieldPath(root reflect.Type, structNamespace string) []pathSegment {
+	names := splitNamespace(structNamespace)
+	if t := indirectType(root); t != nil && t.Name() != "" {
+		names = names[1:] // the first name is the root struct name, absent for anonymous structs
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	segments := make([]pathSegment, 0, len(names))
+	current := root
+	for _, name := range names {
+		var indexes []string
+		if i := strings.IndexByte(name, '['); i >= 0 {
+			indexes = splitIndexes(name[i:])
+			name = name[:i]
+		}
+
+		segment := pathSegment{name: name, indexes: indexes}
+		current = valueType(current)
+		if current != nil && current.Kind() == reflect.Struct {
+			if field, ok := current.FieldByName(name); ok {
+				segment.name = fieldName(field)
+				segment.label = field.Tag.Get("custom")
+				segment.source = fieldSource(field)
+				current = field.Type
+			} else {
+				current = nil
+			}
+		} else {
+			current = nil
+		}
+
+		for range indexes {
+			if current = valueType(current); current != nil {
+				switch current.Kind() {
+				case reflect.Slice, reflect.Array, reflect.Map:
+					current = current.Elem()
+				default:
+					current = nil
+				}
+			}
+		}
+
+		if segment.name == "" {
+			continue // embedded struct flattened into its parent
+		}
+		segments = append(segments, segment)
+	}
+
+	return segments
+}
+
+// parentType returns the struct type holding the field of a validator struct namespace,
+// or nil if it cannot be resolved.
+func parentType(root reflect.Type, structNamespace string) reflect.Type {
+	names := splitNamespace(structNamespace)
+	if t := indirectType(root); t != nil && t.Name() != "" {
+		names = names[1:] // the first name is the root struct name, absent for anonymous structs
+	}
+
+	current := valueType(root)
+	for i := 0; i < len(names)-1 && current != nil; i++ {
+		name, indexes := names[i], ""
+		if j := strings.IndexByte(name, '['); j >= 0 {
+			name, indexes = name[:j], name[j:]
+		}
+
+		if current.Kind() != reflect.Struct {
+			return nil
+		}
+		field, ok := current.FieldByName(name)
+		if !ok {
+			return nil
+		}
+
+		current = valueType(field.Type)
+		for range splitIndexes(indexes) {
+			switch current.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				current = valueType(current.Elem())
+			default:
+				return nil
+			}
+		}
+	}
+
+	if current == nil || current.Kind() != reflect.Struct {
+		return nil
+	}
+	return current
+}
+
+// requestSource returns the request source of the outermost field of a validator struct namespace,
+// or an empty string when it is bound from the body.
+func requestSource(root reflect.Type, structNamespace string) string {
+	if segments := fieldPath(root, structNamespace); len(segments) > 0 {
+		return segments[0].source
+	}
+	return ""
+}
+
+// fieldLabel returns the name messages use for the field: its `custom` label, or the name the field is bound from.
+func fieldLabel(field reflect.StructField) string {
+	if label := field.Tag.Get("custom"); label != "" {
+		return label
+	}
+	if name := fieldName(field); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// fieldName returns the name the field is bound from: the name encoding/json uses for it, its form, CSV column or XML
+// element name or, for fields bound from the query, path params or headers, the name of their fiber tag. It returns an
+// empty string for embedded structs without a name.
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name != "" && name != "-" {
+		return name
+	}
+	for _, tag := range []string{"form", "csv"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	if name, _, _ := strings.Cut(field.Tag.Get("xml"), ","); name != "" && name != "-" {
+		// the local name of the innermost element of namespaced ("ns name") and nested ("a>b") names
+		return name[strings.LastIndexAny(name, " >")+1:]
+	}
+	for _, st := range sourceTags {
+		if name, _, _ := strings.Cut(field.Tag.Get(st.tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct {
+		return ""
+	}
+	return field.Name
+}
+
+// fieldSource returns the request source of the field according to its fiber tags,
+// or an empty string when it is bound from the body.
+func fieldSource(field reflect.StructField) string {
+	for _, st := range sourceTags {
+		if _, ok := field.Tag.Lookup(st.tag); ok {
+			return st.source
+		}
+	}
+	return ""
+}
+
+// splitIndexes splits bracketed indexes (e.g. [2][key]) into their values (e.g. 2 and key).
+func splitIndexes(s string) []string {
+	var indexes []string
+	for s != "" {
+		end := strings.IndexByte(s, ']')
+		if s[0] != '[' || end < 0 {
+			break
+		}
+		indexes = append(indexes, s[1:end])
+		s = s[end+1:]
+	}
+	return indexes
+}
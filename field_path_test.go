@@ -0,0 +1,99 @@
+package kit_test
+
+import (
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructTranslatedFieldPaths(t *testing.T) {
+	type Document struct {
+		Number string `json:"number" validate:"required" custom:"Número"`
+		Kind   string `json:"kind" validate:"required"`
+	}
+
+	type Beneficiary struct {
+		Name     string            `json:"name" validate:"required"`
+		Document Document          `json:"document" custom:"Documento"`
+		Phones   []string          `json:"phones" validate:"dive,required" custom:"Telefone"`
+		Notes    map[string]string `json:"notes" validate:"dive,required" custom:"Observação"`
+	}
+
+	type Audit struct {
+		CreatedBy string `json:"created_by" validate:"required"`
+	}
+
+	type Contract struct {
+		Audit
+		Holder        Beneficiary    `json:"holder" custom:"Titular"`
+		Beneficiaries []Beneficiary  `json:"beneficiaries" validate:"dive" custom:"Beneficiário"`
+		Dependents    []*Beneficiary `json:"dependents" validate:"dive"`
+	}
+
+	valid := Beneficiary{Name: "Ana", Document: Document{Number: "1", Kind: "cpf"}}
+	invalid := Beneficiary{
+		Document: Document{Kind: "cpf"},
+		Phones:   []string{"11912345678", ""},
+		Notes:    map[string]string{"alergia": ""},
+	}
+
+	tests := []struct {
+		name               string
+		input              any
+		locale             string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:   "Labels compose with 1-based indexes",
+			input:  Contract{Audit: Audit{CreatedBy: "x"}, Holder: valid, Beneficiaries: []Beneficiary{valid, valid, valid, invalid}},
+			locale: kit.LocalePtBR,
+			expectedViolations: []kit.Violation{
+				{Field: "beneficiaries[3].name", Tag: "required", Value: "", Message: "beneficiaries[3].name é um campo obrigatório"},
+				{Field: "beneficiaries[3].document.number", Tag: "required", Value: "", Message: "Beneficiário 4 › Documento › Número é um campo obrigatório"},
+				{Field: "beneficiaries[3].phones[1]", Tag: "required", Value: "", Message: "Beneficiário 4 › Telefone 2 é um campo obrigatório"},
+				{Field: "beneficiaries[3].notes[alergia]", Tag: "required", Value: "", Message: "Beneficiário 4 › Observação alergia é um campo obrigatório"},
+			},
+		},
+		{
+			name:   "Fields without label use the JSON path",
+			input:  Contract{Holder: Beneficiary{Name: "Ana", Document: Document{Number: "1"}}},
+			locale: kit.LocaleEn,
+			expectedViolations: []kit.Violation{
+				{Field: "created_by", Tag: "required", Value: "", Message: "created_by is a required field"},
+				{Field: "holder.document.kind", Tag: "required", Value: "", Message: "holder.document.kind is a required field"},
+			},
+		},
+		{
+			name:   "Unlabeled parents keep their JSON path",
+			input:  &Contract{Audit: Audit{CreatedBy: "x"}, Holder: valid, Dependents: []*Beneficiary{{Name: "Bia", Document: Document{Kind: "cpf"}}}},
+			locale: kit.LocaleEs,
+			expectedViolations: []kit.Violation{
+				{Field: "dependents[0].document.number", Tag: "required", Value: "", Message: "dependents[0] › Documento › Número es un campo requerido"},
+			},
+		},
+		{
+			name: "Anonymous struct",
+			input: struct {
+				Items []Document `json:"items" validate:"dive" custom:"Item"`
+			}{Items: []Document{{Kind: "cpf"}}},
+			locale: kit.LocalePtBR,
+			expectedViolations: []kit.Violation{
+				{Field: "items[0].number", Tag: "required", Value: "", Message: "Item 1 › Número é um campo obrigatório"},
+			},
+		},
+	}
+
+	validator := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StructTranslatedLocale(tt.input, tt.locale)
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedViolations, validationErr.Violations())
+		})
+	}
+}
@@ -1,14 +1,18 @@
 // Package kit provides utilities for handling HTTP request parsing and validation
-// in Go applications. This file defines the ParseRequestBody function, which
-// simplifies parsing incoming HTTP request bodies into structured Go objects
-// and validates them using a provided Validator, ensuring robust input handling.
+// in Go applications. This file defines the ParseRequestBody and ParseRequest functions, which
+// simplify parsing incoming HTTP request bodies, query strings, path params and headers into
+// structured Go objects and validate them using a provided Validator, ensuring robust input handling.
 
 package kit
 
 import (
 	"errors"
+	"mime/multipart"
+	"reflect"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 )
 
 type Validator interface {
@@ -18,17 +22,266 @@ type Validator interface {
 func ParseRequestBody(out any, c *fiber.Ctx, v Validator) error {
 	// parse and validate the request body using the Fiber context
 	if err := c.BodyParser(out); err != nil {
-		return HTTPBadRequestError("bad-input", err)
+		return CodeBadInput.New(err)
 	}
 
-	// validate the parsed body using the provided Validator
-	if err := v.StructTranslated(out); err != nil {
+	return validateRequest(out, c, v, SourceBody)
+}
+
+// BodyConfig defines the configuration for ParseRequestBodyWithConfig. JSON bodies are decoded with encoding/json,
+// rejecting data after the JSON value as json.Unmarshal does, and decoder errors are reported as translated,
+// field-aware details. The zero value applies no other rule.
+type BodyConfig struct {
+	// RequireJSON rejects requests whose Content-Type is not application/json, or a +json media type,
+	// with an unsupported-media-type error. Otherwise, other content types are parsed by fiber's BodyParser.
+	RequireJSON bool
+	// MaxBodySize rejects bodies larger than the given number of bytes with a request-too-large error.
+	// It is checked once fiber has read the whole body into memory, so it narrows the limit per route but
+	// gives no memory protection: the BodyLimit of the fiber app is the limit of what is read.
+	// Zero means no limit other than the one of the fiber app.
+	MaxBodySize int
+	// MaxDepth rejects JSON documents nesting objects and arrays deeper than the given number of levels.
+	// Zero means no limit.
+	MaxDepth int
+	// DisallowUnknownFields rejects object members that do not match any field of the target struct.
+	DisallowUnknownFields bool
+	// DisallowDuplicateKeys rejects objects declaring the same key more than once.
+	DisallowDuplicateKeys bool
+}
+
+// ParseRequestBodyWithConfig parses the request body into out as configured by the given BodyConfig and
+// validates it. JSON decoder errors are returned as bad-input errors whose details address the rejected
+// fields, e.g. "idade: esperado número, recebido texto", translated to the locale of the request.
+func ParseRequestBodyWithConfig(out any, c *fiber.Ctx, v Validator, config BodyConfig) error {
+	body := c.Body()
+	if config.MaxBodySize > 0 && len(body) > config.MaxBodySize {
+		return CodeRequestTooLarge.New(nil)
+	}
+
+	if isJSONContentType(c) {
+		if err := decodeJSONBody(body, out, config, RequestLocale(c)); err != nil {
+			return err
+		}
+	} else if config.RequireJSON {
+		return CodeUnsupportedMediaType.New(nil)
+	} else if err := c.BodyParser(out); err != nil {
+		return CodeBadInput.New(err)
+	}
+
+	return validateRequest(out, c, v, SourceBody)
+}
+
+// isJSONContentType reports whether the request Content-Type is application/json or a +json media type.
+func isJSONContentType(c *fiber.Ctx) bool {
+	mediaType, _, _ := strings.Cut(string(c.Request().Header.ContentType()), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == fiber.MIMEApplicationJSON || strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")
+}
+
+// ParseMultipartForm binds the values and files of a multipart/form-data request into out, following the `form`
+// tags of its fields, and validates it. Files are bound to fields of type *multipart.FileHeader, receiving the first
+// file of their name, or []*multipart.FileHeader, receiving all of them, and can be validated with the
+// file_max_size, file_mime and file_max_pages tags. Requests of other content types are rejected with an
+// unsupported-media-type error.
+func ParseMultipartForm(out any, c *fiber.Ctx, v Validator) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		if errors.Is(err, fasthttp.ErrNoMultipartForm) {
+			return CodeUnsupportedMediaType.New(nil)
+		}
+		return CodeBadInput.New(err)
+	}
+
+	if err := c.BodyParser(out); err != nil {
+		return CodeBadInput.New(err)
+	}
+	bindFiles(reflect.ValueOf(out), form.File)
+
+	return validateRequest(out, c, v, SourceBody)
+}
+
+// bindFiles sets the *multipart.FileHeader and []*multipart.FileHeader fields of the struct pointed by out,
+// and of its embedded structs, to the files of their form name.
+func bindFiles(out reflect.Value, files map[string][]*multipart.FileHeader) {
+	for out.Kind() == reflect.Pointer {
+		if out.IsNil() {
+			return
+		}
+		out = out.Elem()
+	}
+	if out.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range out.NumField() {
+		field, value := out.Type().Field(i), out.Field(i)
+		if field.Anonymous {
+			bindFiles(value.Addr(), files)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if len(files[name]) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case reflect.TypeFor[*multipart.FileHeader]():
+			value.Set(reflect.ValueOf(files[name][0]))
+		case reflect.TypeFor[[]*multipart.FileHeader]():
+			value.Set(reflect.ValueOf(files[name]))
+		}
+	}
+}
+
+// ParseRequest binds the body, path params, query string and headers of the request into out, following the
+// `json`, `params`, `query` and `reqHeader` tags of its fields, and validates the combined struct.
+// The body is parsed only when present, and the other sources only when out declares fields tagged for them,
+// in that order. Fields tagged for the other sources are never bound from the body, even when it has a member
+// of their name. Each violation reports the source of its field, untagged fields being reported as body.
+func ParseRequest(out any, c *fiber.Ctx, v Validator) error {
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(out); err != nil {
+			return CodeBadInput.New(err)
+		}
+		// encoding/json matches members to untagged fields by their Go name, so reset the fields of the other sources
+		clearSourceFields(reflect.ValueOf(out))
+	}
+
+	for _, st := range sourceTags {
+		if hasSourceTag(reflect.TypeOf(out), st.tag) {
+			if err := bindSource(out, c, st.source); err != nil {
+				return err
+			}
+		}
+	}
+
+	return validateRequest(out, c, v, SourceBody)
+}
+
+// ParseQuery binds the query string of the request into out, following the `query` tags of its fields,
+// and validates it.
+func ParseQuery(out any, c *fiber.Ctx, v Validator) error {
+	return parseSource(out, c, v, SourceQuery)
+}
+
+// ParseParams binds the path params of the request into out, following the `params` tags of its fields,
+// and validates it.
+func ParseParams(out any, c *fiber.Ctx, v Validator) error {
+	return parseSource(out, c, v, SourcePath)
+}
+
+// ParseHeaders binds the headers of the request into out, following the `reqHeader` tags of its fields,
+// and validates it.
+func ParseHeaders(out any, c *fiber.Ctx, v Validator) error {
+	return parseSource(out, c, v, SourceHeader)
+}
+
+// parseSource binds a single request source into out and validates it, reporting violations from that source.
+func parseSource(out any, c *fiber.Ctx, v Validator, source string) error {
+	if err := bindSource(out, c, source); err != nil {
+		return err
+	}
+	return validateRequest(out, c, v, source)
+}
+
+// bindSource binds the query string, path params or headers of the request into out.
+func bindSource(out any, c *fiber.Ctx, source string) error {
+	var err error
+	switch source {
+	case SourceQuery:
+		err = c.QueryParser(out)
+	case SourcePath:
+		err = c.ParamsParser(out)
+	case SourceHeader:
+		err = c.ReqHeaderParser(out)
+	}
+
+	if err != nil {
+		return CodeBadInput.New(err)
+	}
+	return nil
+}
+
+// clearSourceFields resets the fields tagged for the query string, path params or headers of the struct pointed by
+// out, and of its embedded structs, to their zero value.
+func clearSourceFields(out reflect.Value) {
+	for out.Kind() == reflect.Pointer {
+		if out.IsNil() {
+			return
+		}
+		out = out.Elem()
+	}
+	if out.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range out.NumField() {
+		field, value := out.Type().Field(i), out.Field(i)
+		switch {
+		case !field.IsExported() && !field.Anonymous:
+		case fieldSource(field) != "":
+			value.SetZero()
+		case field.Anonymous:
+			clearSourceFields(value)
+		}
+	}
+}
+
+// hasSourceTag reports whether the struct type, or any of its embedded structs, declares a field with the tag.
+func hasSourceTag(t reflect.Type, tag string) bool {
+	t = indirectType(t)
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return true
+		}
+		if field.Anonymous && hasSourceTag(field.Type, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRequest normalizes and validates the parsed request, reporting violations of fields without a source tag
+// as coming from the defaultSource.
+func validateRequest(out any, c *fiber.Ctx, v Validator, defaultSource string) error {
+	// apply the `normalize` tags before validating, so that rules see the normalized values
+	if err := Normalize(out); err != nil {
+		return err
+	}
+
+	// validate the parsed request using the provided Validator, translating messages to the request locale when supported
+	if err := validateTranslated(out, c, v); err != nil {
 		var validationErrors *ValidationErrors
 		if errors.As(err, &validationErrors) {
-			return HTTPBadRequestError("request-validation", err)
+			validationErrors.setDefaultSource(defaultSource)
+			return CodeRequestValidation.New(err)
 		}
 		return err
 	}
 
 	return nil
 }
+
+// validateTranslated validates the struct, translating the messages to the locale of the request
+// when the Validator is a LocalizedValidator, and with the request context when it is a ContextValidator.
+func validateTranslated(out any, c *fiber.Ctx, v Validator) error {
+	if cv, ok := v.(ContextValidator); ok {
+		return cv.StructTranslatedLocaleCtx(requestContext{Context: c.UserContext(), c: c}, out, RequestLocale(c))
+	}
+	if lv, ok := v.(LocalizedValidator); ok {
+		return lv.StructTranslatedLocale(out, RequestLocale(c))
+	}
+	return v.StructTranslated(out)
+}
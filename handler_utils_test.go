@@ -1,12 +1,18 @@
 package kit_test
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/arvo-health/kit"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 )
 
@@ -73,3 +79,378 @@ func TestParseRequestBody(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequestBodyLocale(t *testing.T) {
+	type TestInput struct {
+		Name string `json:"name" validate:"required" custom:"Name"`
+	}
+
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		expectedMessage string
+	}{
+		{name: "Default locale", acceptLanguage: "", expectedMessage: "Name é um campo obrigatório"},
+		{name: "English", acceptLanguage: "en-US", expectedMessage: "Name is a required field"},
+		{name: "Spanish", acceptLanguage: "es", expectedMessage: "Name es un campo requerido"},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			c := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(c)
+
+			c.Request().Header.SetContentType("application/json")
+			c.Request().Header.Set(fiber.HeaderAcceptLanguage, tt.acceptLanguage)
+			c.Request().SetBody([]byte(`{"name":""}`))
+
+			var output TestInput
+			err := kit.ParseRequestBody(&output, c, v)
+
+			var httpError *kit.HTTPError
+			assert.ErrorAs(t, err, &httpError)
+			assert.Equal(t, "request-validation", httpError.Slug)
+			assert.Equal(t, []string{tt.expectedMessage}, httpError.DetailMessages())
+		})
+	}
+}
+
+// parseRoute serves the request through a route with an :id param, returning the error of parse.
+func parseRoute(t *testing.T, method, target, body string, headers map[string]string, parse func(c *fiber.Ctx) error) error {
+	t.Helper()
+
+	var parseErr error
+	app := fiber.New()
+	app.Add(method, "/contracts/:id", func(c *fiber.Ctx) error {
+		parseErr = parse(c)
+		return nil
+	})
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	return parseErr
+}
+
+func TestParseRequest(t *testing.T) {
+	type Pagination struct {
+		Page int `query:"page" validate:"gte=1"`
+	}
+
+	type UpdateContract struct {
+		Pagination
+		ID        int    `params:"id" validate:"gte=1"`
+		Size      int    `query:"size" validate:"lte=100" custom:"Tamanho"`
+		RequestID string `reqHeader:"X-Request-ID" validate:"required,uuid"`
+		Name      string `json:"name" validate:"required" custom:"Nome"`
+	}
+
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		body               string
+		headers            map[string]string
+		expectedOutput     UpdateContract
+		expectedSlug       string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:    "Binds every source",
+			method:  fiber.MethodPut,
+			target:  "/contracts/42?page=2&size=10",
+			body:    `{"name":"Plano Ouro"}`,
+			headers: map[string]string{"X-Request-ID": "0f9c8e8e-7e3a-4c1b-9b7e-2f1d7c6a5b4e"},
+			expectedOutput: UpdateContract{
+				Pagination: Pagination{Page: 2},
+				ID:         42,
+				Size:       10,
+				RequestID:  "0f9c8e8e-7e3a-4c1b-9b7e-2f1d7c6a5b4e",
+				Name:       "Plano Ouro",
+			},
+		},
+		{
+			name:    "Does not bind fields of other sources from the body",
+			method:  fiber.MethodPut,
+			target:  "/contracts/42?page=2",
+			body:    `{"name":"Plano Ouro","page":5,"size":500,"requestid":"0f9c8e8e-7e3a-4c1b-9b7e-2f1d7c6a5b4e"}`,
+			headers: map[string]string{"X-Request-ID": "1d2e3f40-5a6b-4c7d-8e9f-0a1b2c3d4e5f"},
+			expectedOutput: UpdateContract{
+				Pagination: Pagination{Page: 2},
+				ID:         42,
+				RequestID:  "1d2e3f40-5a6b-4c7d-8e9f-0a1b2c3d4e5f",
+				Name:       "Plano Ouro",
+			},
+		},
+		{
+			name:         "Body cannot set a header field",
+			method:       fiber.MethodPut,
+			target:       "/contracts/42?page=1",
+			body:         `{"name":"Plano Ouro","requestid":"0f9c8e8e-7e3a-4c1b-9b7e-2f1d7c6a5b4e"}`,
+			expectedSlug: "request-validation",
+			expectedViolations: []kit.Violation{
+				{Field: "X-Request-ID", Source: kit.SourceHeader, Tag: "required", Value: "", Message: "X-Request-ID é um campo obrigatório"},
+			},
+		},
+		{
+			name:         "Reports the source of each violation",
+			method:       fiber.MethodGet,
+			target:       "/contracts/0?page=0&size=500",
+			expectedSlug: "request-validation",
+			expectedViolations: []kit.Violation{
+				{Field: "page", Source: kit.SourceQuery, Tag: "gte", Param: "1", Value: 0, Message: "page deve ser 1 ou superior"},
+				{Field: "id", Source: kit.SourcePath, Tag: "gte", Param: "1", Value: 0, Message: "id deve ser 1 ou superior"},
+				{Field: "size", Source: kit.SourceQuery, Tag: "lte", Param: "100", Value: 500, Message: "Tamanho deve ser 100 ou menor"},
+				{Field: "X-Request-ID", Source: kit.SourceHeader, Tag: "required", Value: "", Message: "X-Request-ID é um campo obrigatório"},
+				{Field: "name", Source: kit.SourceBody, Tag: "required", Value: "", Message: "Nome é um campo obrigatório"},
+			},
+		},
+		{
+			name:         "Invalid query value",
+			method:       fiber.MethodGet,
+			target:       "/contracts/1?page=abc",
+			expectedSlug: "bad-input",
+		},
+		{
+			name:         "Invalid body",
+			method:       fiber.MethodPut,
+			target:       "/contracts/1",
+			body:         `{"name":`,
+			expectedSlug: "bad-input",
+		},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output UpdateContract
+			err := parseRoute(t, tt.method, tt.target, tt.body, tt.headers, func(c *fiber.Ctx) error {
+				return kit.ParseRequest(&output, c, v)
+			})
+
+			if tt.expectedSlug == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, output)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug)
+			assert.Equal(t, tt.expectedViolations, httpError.Details)
+		})
+	}
+}
+
+func TestParseRequestSourceFunctions(t *testing.T) {
+	type Query struct {
+		Status string `query:"status" validate:"required,oneof=active inactive"`
+	}
+
+	type Params struct {
+		ID string `params:"id" validate:"uuid"`
+	}
+
+	type Headers struct {
+		Tenant string `reqHeader:"X-Tenant" validate:"required" custom:"Empresa"`
+	}
+
+	v := kit.NewValidator()
+
+	tests := []struct {
+		name               string
+		target             string
+		headers            map[string]string
+		parse              func(c *fiber.Ctx) error
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:   "Valid query",
+			target: "/contracts/1?status=active",
+			parse:  func(c *fiber.Ctx) error { return kit.ParseQuery(&Query{}, c, v) },
+		},
+		{
+			name:   "Invalid query",
+			target: "/contracts/1?status=unknown",
+			parse:  func(c *fiber.Ctx) error { return kit.ParseQuery(&Query{}, c, v) },
+			expectedViolations: []kit.Violation{
+				{Field: "status", Source: kit.SourceQuery, Tag: "oneof", Param: "active inactive", Value: "unknown", Message: "status deve ser um de [active inactive]"},
+			},
+		},
+		{
+			name:   "Valid params",
+			target: "/contracts/0f9c8e8e-7e3a-4c1b-9b7e-2f1d7c6a5b4e",
+			parse:  func(c *fiber.Ctx) error { return kit.ParseParams(&Params{}, c, v) },
+		},
+		{
+			name:   "Invalid params",
+			target: "/contracts/1",
+			parse:  func(c *fiber.Ctx) error { return kit.ParseParams(&Params{}, c, v) },
+			expectedViolations: []kit.Violation{
+				{Field: "id", Source: kit.SourcePath, Tag: "uuid", Value: "1", Message: "id deve ser um UUID válido"},
+			},
+		},
+		{
+			name:    "Valid headers",
+			target:  "/contracts/1",
+			headers: map[string]string{"X-Tenant": "arvo"},
+			parse:   func(c *fiber.Ctx) error { return kit.ParseHeaders(&Headers{}, c, v) },
+		},
+		{
+			name:   "Invalid headers",
+			target: "/contracts/1",
+			parse:  func(c *fiber.Ctx) error { return kit.ParseHeaders(&Headers{}, c, v) },
+			expectedViolations: []kit.Violation{
+				{Field: "X-Tenant", Source: kit.SourceHeader, Tag: "required", Value: "", Message: "Empresa é um campo obrigatório"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseRoute(t, fiber.MethodGet, tt.target, "", tt.headers, tt.parse)
+
+			if tt.expectedViolations == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, "request-validation", httpError.Slug)
+			assert.Equal(t, tt.expectedViolations, httpError.Details)
+		})
+	}
+}
+
+func TestParseMultipartForm(t *testing.T) {
+	type Upload struct {
+		ClaimID     string                  `form:"claim_id" validate:"required" custom:"Guia"`
+		Report      *multipart.FileHeader   `form:"report" validate:"required,file_max_size=1MB,file_mime=application/pdf,file_max_pages=5" custom:"Laudo"`
+		Attachments []*multipart.FileHeader `form:"attachments" validate:"max=2,dive,file_mime=application/pdf image/*" custom:"Anexo"`
+	}
+
+	type part struct {
+		field, filename string
+		content         []byte
+	}
+
+	tests := []struct {
+		name               string
+		contentType        string
+		parts              []part
+		expectedSlug       string
+		expectedViolations []kit.Violation
+		assertOutput       func(t *testing.T, output Upload)
+	}{
+		{
+			name: "Binds values and files",
+			parts: []part{
+				{field: "claim_id", content: []byte("123")},
+				{field: "report", filename: "laudo.pdf", content: testPDF},
+				{field: "attachments", filename: "a.png", content: testPNG},
+				{field: "attachments", filename: "b.pdf", content: testPDF},
+			},
+			assertOutput: func(t *testing.T, output Upload) {
+				assert.Equal(t, "123", output.ClaimID)
+				require.NotNil(t, output.Report)
+				assert.Equal(t, "laudo.pdf", output.Report.Filename)
+				require.Len(t, output.Attachments, 2)
+				assert.Equal(t, "a.png", output.Attachments[0].Filename)
+				assert.Equal(t, "b.pdf", output.Attachments[1].Filename)
+			},
+		},
+		{
+			name: "Invalid files",
+			parts: []part{
+				{field: "report", filename: "laudo.pdf", content: testPNG},
+				{field: "attachments", filename: "a.txt", content: []byte("hello")},
+				{field: "attachments", filename: "b.pdf", content: testPDF},
+				{field: "attachments", filename: "c.pdf", content: testPDF},
+			},
+			expectedSlug: "request-validation",
+			expectedViolations: []kit.Violation{
+				{Field: "claim_id", Source: kit.SourceBody, Tag: "required", Value: "", Message: "Guia é um campo obrigatório"},
+				{Field: "report", Source: kit.SourceBody, Tag: "file_mime", Param: "application/pdf", Value: "laudo.pdf", Message: "Laudo deve ser um arquivo do tipo application/pdf"},
+				{Field: "attachments", Source: kit.SourceBody, Tag: "max", Param: "2", Value: []string{"a.txt", "b.pdf", "c.pdf"}, Message: "Anexo deve conter no máximo 2 itens"},
+			},
+		},
+		{
+			name:               "Missing file",
+			parts:              []part{{field: "claim_id", content: []byte("123")}},
+			expectedSlug:       "request-validation",
+			expectedViolations: []kit.Violation{{Field: "report", Source: kit.SourceBody, Tag: "required", Message: "Laudo é um campo obrigatório"}},
+		},
+		{
+			name:         "Not a multipart request",
+			contentType:  fiber.MIMEApplicationJSON,
+			expectedSlug: "unsupported-media-type",
+		},
+		{
+			name:         "Malformed multipart body",
+			contentType:  fiber.MIMEMultipartForm + "; boundary=missing",
+			expectedSlug: "bad-input",
+		},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			w := multipart.NewWriter(&body)
+			for _, p := range tt.parts {
+				var pw io.Writer
+				var err error
+				if p.filename == "" {
+					pw, err = w.CreateFormField(p.field)
+				} else {
+					pw, err = w.CreateFormFile(p.field, p.filename)
+				}
+				require.NoError(t, err)
+				_, err = pw.Write(p.content)
+				require.NoError(t, err)
+			}
+			require.NoError(t, w.Close())
+
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = w.FormDataContentType()
+			}
+
+			app := fiber.New()
+			c := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(c)
+
+			c.Request().Header.SetContentType(contentType)
+			c.Request().SetBody(body.Bytes())
+
+			var output Upload
+			err := kit.ParseMultipartForm(&output, c, v)
+
+			if tt.expectedSlug == "" {
+				require.NoError(t, err)
+				tt.assertOutput(t, output)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug)
+			if tt.expectedViolations != nil {
+				assert.Equal(t, tt.expectedViolations, httpError.Details)
+			}
+		})
+	}
+}
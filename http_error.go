@@ -6,18 +6,23 @@ package kit
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 // HTTPInternalServerError creates an HTTPError with status 500 and slug "unknown-error" for the given error.
 func HTTPInternalServerError(err error) *HTTPError {
-	return NewHTTPError(http.StatusInternalServerError, "unknown-error", err)
+	return NewHTTPError(CodeUnknownError.Status, CodeUnknownError.Slug, err)
 }
 
 // HTTPUnauthorizedError creates an HTTPError with status 401 and slug "unauthorized" for the given error.
 func HTTPUnauthorizedError(err error) *HTTPError {
-	return NewHTTPError(http.StatusUnauthorized, "unauthorized", err)
+	return NewHTTPError(CodeUnauthorized.Status, CodeUnauthorized.Slug, err)
 }
 
 // HTTPForbiddenError creates an HTTPError with status code 403 (Forbidden), a slug, and an optional underlying error.
@@ -45,14 +50,79 @@ func HTTPNotFoundError(slug string, err error) *HTTPError {
 	return NewHTTPError(http.StatusNotFound, slug, err)
 }
 
+// HTTPMethodNotAllowedError creates an HTTPError with status 405 and slug "method-not-allowed",
+// listing the allowed methods in the Allow header.
+func HTTPMethodNotAllowedError(err error, allowed ...string) *HTTPError {
+	e := NewHTTPError(CodeMethodNotAllowed.Status, CodeMethodNotAllowed.Slug, err)
+	if len(allowed) > 0 {
+		e = e.WithHeader(fiber.HeaderAllow, strings.Join(allowed, ", "))
+	}
+	return e
+}
+
+// HTTPRequestTimeoutError creates an HTTPError with status 408 and slug "request-timeout" for the given error.
+func HTTPRequestTimeoutError(err error) *HTTPError {
+	return NewHTTPError(CodeRequestTimeout.Status, CodeRequestTimeout.Slug, err)
+}
+
+// HTTPRequestEntityTooLargeError creates an HTTPError with status 413 and slug "request-too-large" for the given error.
+func HTTPRequestEntityTooLargeError(err error) *HTTPError {
+	return NewHTTPError(CodeRequestTooLarge.Status, CodeRequestTooLarge.Slug, err)
+}
+
+// HTTPUnsupportedMediaTypeError creates an HTTPError with status 415 and slug "unsupported-media-type" for the given error.
+func HTTPUnsupportedMediaTypeError(err error) *HTTPError {
+	return NewHTTPError(CodeUnsupportedMediaType.Status, CodeUnsupportedMediaType.Slug, err)
+}
+
+// HTTPTooManyRequestsError creates an HTTPError with status 429 and slug "too-many-requests",
+// telling the client when to retry through the Retry-After header when retryAfter is positive.
+func HTTPTooManyRequestsError(retryAfter time.Duration, err error) *HTTPError {
+	return NewHTTPError(CodeTooManyRequests.Status, CodeTooManyRequests.Slug, err).WithRetryAfter(retryAfter)
+}
+
+// HTTPBadGatewayError creates an HTTPError with status 502 and slug "upstream-error" for the error returned
+// by the upstream service.
+func HTTPBadGatewayError(err error) *HTTPError {
+	return NewHTTPError(CodeUpstreamError.Status, CodeUpstreamError.Slug, err)
+}
+
+// HTTPServiceUnavailableError creates an HTTPError with status 503 and slug "service-unavailable",
+// telling the client when to retry through the Retry-After header when retryAfter is positive.
+func HTTPServiceUnavailableError(retryAfter time.Duration, err error) *HTTPError {
+	return NewHTTPError(CodeServiceUnavailable.Status, CodeServiceUnavailable.Slug, err).WithRetryAfter(retryAfter)
+}
+
+// HTTPGatewayTimeoutError creates an HTTPError with status 504 and slug "timeout" for the error of the timed out
+// upstream call.
+func HTTPGatewayTimeoutError(err error) *HTTPError {
+	return NewHTTPError(CodeTimeout.Status, CodeTimeout.Slug, err)
+}
+
 // HTTPError represents a structured error used for API responses, including status, code, message, cause, and details.
+// The cause and the stack trace are kept for logging and error matching, and are never serialized to the client.
+// Header holds the response headers ErrorHandler writes along with the error, such as Retry-After or Allow.
+//
+// The constructors of the statuses with a catalog code, such as HTTPTooManyRequestsError, use its slug, while those
+// of the statuses of domain errors, such as HTTPNotFoundError, take the slug of the error. The With methods return
+// a copy of the HTTPError, so package-level sentinels can be extended per request without being modified.
 type HTTPError struct {
-	Slug    string   `json:"code"`
-	Message string   `json:"message"`
-	Details []string `json:"details,omitempty"`
-	Status  int      `json:"status_code"`
+	Slug     string         `json:"code"`
+	Message  string         `json:"message"`
+	Details  []Violation    `json:"details,omitempty"`
+	Status   int            `json:"status_code"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Header   http.Header    `json:"-"`
+
+	cause    error
+	stack    []uintptr
+	messages map[string]string // localized messages of the error code, when the message is its default
 }
 
+// CaptureStackTrace enables capturing the stack trace of the caller whenever an HTTPError is created.
+// It is disabled by default since capturing the stack on every error has a cost; see also HTTPError.WithStack.
+var CaptureStackTrace = false
+
 // NewHTTPError generates a HTTPError from the provided HTTP status and error, mapping to structured error types.
 func NewHTTPError(status int, slug string, err error) *HTTPError {
 	if status == 0 {
@@ -60,25 +130,33 @@ func NewHTTPError(status int, slug string, err error) *HTTPError {
 	}
 
 	if slug == "" {
-		slug = "unknown-error"
+		slug = CodeUnknownError.Slug
 	}
 
+	cause := err
 	if err == nil {
 		err = errors.New("unknown error")
 	}
 
-	var details []string
+	var details []Violation
 	var validationErrs *ValidationErrors
 	if errors.As(err, &validationErrs) {
-		details = validationErrs.Validations()
+		details = validationErrs.Violations()
 	}
 
-	return &HTTPError{
+	e := &HTTPError{
 		Status:  status,
 		Slug:    slug,
 		Message: err.Error(),
 		Details: details,
+		cause:   cause,
+	}
+
+	if CaptureStackTrace {
+		e.stack = callers()
 	}
+
+	return e
 }
 
 // Error returns the error message contained within the HTTPError structure.
@@ -86,10 +164,114 @@ func (e *HTTPError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying cause of the HTTPError, allowing errors.Is and errors.As to inspect it.
+func (e *HTTPError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an HTTPError with the same slug and status, so that sentinel HTTPError values such as
+// `var ErrUserNotFound = kit.HTTPNotFoundError("user-not-found", nil)` match, through errors.Is,
+// any error carrying their slug and status regardless of its message or cause.
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError) //nolint:errorlint // errors.Is compares each error of the chain with the target
+	return ok && t.Slug != "" && t.Slug == e.Slug && t.Status == e.Status
+}
+
+// IsClientError reports whether the HTTPError or fiber.Error found in the error chain has a 4xx status.
+func IsClientError(err error) bool {
+	status := errorStatus(err)
+	return status >= http.StatusBadRequest && status < http.StatusInternalServerError
+}
+
+// IsServerError reports whether the HTTPError or fiber.Error found in the error chain has a 5xx status.
+func IsServerError(err error) bool {
+	return errorStatus(err) >= http.StatusInternalServerError
+}
+
+// errorStatus returns the status of the first HTTPError or fiber.Error found in the error chain, or 0 if none.
+func errorStatus(err error) int {
+	var e *HTTPError
+	if errors.As(err, &e) {
+		return e.Status
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+
+	return 0
+}
+
+// WithHeader returns a copy of the HTTPError with the response header added.
+func (e *HTTPError) WithHeader(key, value string) *HTTPError {
+	c := e.clone()
+	if c.Header == nil {
+		c.Header = http.Header{}
+	}
+	c.Header.Add(key, value)
+	return c
+}
+
+// WithMetadata returns a copy of the HTTPError with the metadata entry, serialized to the client, added.
+func (e *HTTPError) WithMetadata(key string, value any) *HTTPError {
+	c := e.clone()
+	if c.Metadata == nil {
+		c.Metadata = map[string]any{}
+	}
+	c.Metadata[key] = value
+	return c
+}
+
+// WithRetryAfter returns a copy of the HTTPError with the Retry-After header, in whole seconds rounded up,
+// and the retry_after metadata set. Non-positive durations are ignored.
+func (e *HTTPError) WithRetryAfter(d time.Duration) *HTTPError {
+	if d <= 0 {
+		return e
+	}
+
+	seconds := int((d + time.Second - 1) / time.Second)
+	c := e.WithMetadata("retry_after", seconds)
+	if c.Header == nil {
+		c.Header = http.Header{}
+	}
+	c.Header.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return c
+}
+
+// WithStack returns a copy of the HTTPError with the stack trace of the caller captured, if not already captured.
+func (e *HTTPError) WithStack() *HTTPError {
+	if e.stack != nil {
+		return e
+	}
+
+	c := e.clone()
+	c.stack = callers()
+	return c
+}
+
+// clone returns a shallow copy of the HTTPError with its own copies of Header and Metadata.
+func (e *HTTPError) clone() *HTTPError {
+	c := *e
+	c.Header = e.Header.Clone()
+	c.Metadata = maps.Clone(e.Metadata)
+	return &c
+}
+
+// StackTrace returns the captured stack trace as "function file:line" frames, or nil if none was captured.
+func (e *HTTPError) StackTrace() []string {
+	return formatStack(e.stack)
+}
+
+// DetailMessages returns the messages of the validation details, or nil if there are none.
+func (e *HTTPError) DetailMessages() []string {
+	return violationMessages(e.Details)
+}
+
 // String returns a formatted string representation of the HTTPError, including slug, message, and optional details.
 func (e *HTTPError) String() string {
 	if len(e.Details) > 0 {
-		return fmt.Sprintf("[%s] %s (%s)", e.Slug, e.Message, strings.Join(e.Details, ","))
+		return fmt.Sprintf("[%s] %s (%s)", e.Slug, e.Message, strings.Join(e.DetailMessages(), ","))
 	}
 
 	return fmt.Sprintf("[%s] %s", e.Slug, e.Message)
@@ -0,0 +1,132 @@
+// Package kit provides utilities for structured error handling and API response formatting.
+// This file defines functions for decoding the error responses of other kit-based services back into
+// HTTPError, so that errors propagate across service boundaries predictably.
+
+package kit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CodeUpstreamError is the error code of errors returned by an upstream service.
+var CodeUpstreamError = ErrorCode{
+	Slug:   "upstream-error",
+	Status: http.StatusBadGateway,
+	Messages: map[string]string{
+		LocalePtBR: "erro no serviço externo",
+		LocaleEn:   "upstream service error",
+		LocaleEs:   "error en el servicio externo",
+	},
+}
+
+func init() {
+	DefaultErrorCatalog.MustRegister(CodeUpstreamError)
+}
+
+// errorBody is the union of the legacy `{"error": HTTPError}` format and the problem details format.
+type errorBody struct {
+	Error *struct {
+		Slug     string         `json:"code"`
+		Message  string         `json:"message"`
+		Details  []Violation    `json:"details"`
+		Status   int            `json:"status_code"`
+		Metadata map[string]any `json:"metadata"`
+	} `json:"error"`
+
+	Code     string         `json:"code"`
+	Detail   string         `json:"detail"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Details  []Violation    `json:"details"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// DecodeHTTPError turns the status and body of an error response of a kit-based service back into an HTTPError,
+// preserving slug, message, details, metadata and status. Both the legacy and the problem details formats are
+// supported. Bodies in any other format produce an HTTPError with the response status and the raw body as message.
+func DecodeHTTPError(status int, body []byte) *HTTPError {
+	var b errorBody
+	if err := json.Unmarshal(body, &b); err == nil {
+		switch {
+		case b.Error != nil && b.Error.Slug != "":
+			return &HTTPError{
+				Slug:     b.Error.Slug,
+				Message:  b.Error.Message,
+				Details:  b.Error.Details,
+				Status:   firstNonZero(status, b.Error.Status),
+				Metadata: b.Error.Metadata,
+			}
+		case b.Code != "":
+			message := b.Detail
+			if message == "" {
+				message = b.Title
+			}
+			return &HTTPError{
+				Slug:     b.Code,
+				Message:  message,
+				Details:  b.Details,
+				Status:   firstNonZero(status, b.Status),
+				Metadata: b.Metadata,
+			}
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return NewHTTPError(status, "", fmt.Errorf("unexpected error response: %s", message))
+}
+
+// DecodeHTTPResponse reads the body of the error response and decodes it with DecodeHTTPError.
+// The Retry-After header is preserved. The caller remains responsible for closing the body.
+func DecodeHTTPResponse(resp *http.Response) *HTTPError {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return NewHTTPError(resp.StatusCode, "", fmt.Errorf("reading error response: %w", err))
+	}
+
+	e := DecodeHTTPError(resp.StatusCode, body)
+	if retryAfter := resp.Header.Get(fasthttp.HeaderRetryAfter); retryAfter != "" {
+		e = e.WithHeader(fasthttp.HeaderRetryAfter, retryAfter)
+	}
+	return e
+}
+
+// DecodeFastHTTPResponse decodes the fasthttp error response with DecodeHTTPError.
+// The Retry-After header is preserved.
+func DecodeFastHTTPResponse(resp *fasthttp.Response) *HTTPError {
+	e := DecodeHTTPError(resp.StatusCode(), resp.Body())
+	if retryAfter := resp.Header.Peek(fasthttp.HeaderRetryAfter); len(retryAfter) > 0 {
+		e = e.WithHeader(fasthttp.HeaderRetryAfter, string(retryAfter))
+	}
+	return e
+}
+
+// UpstreamError re-wraps an error decoded from an upstream service into a 502 upstream-error,
+// keeping the upstream error as its cause and its slug and status as metadata.
+// For other statuses or slugs, use NewHTTPError with the upstream error, which is also kept as the cause.
+func UpstreamError(upstream *HTTPError) *HTTPError {
+	e := NewHTTPError(CodeUpstreamError.Status, CodeUpstreamError.Slug,
+		fmt.Errorf("upstream error [%s]: %w", upstream.Slug, upstream))
+
+	return e.
+		WithMetadata("upstream_code", upstream.Slug).
+		WithMetadata("upstream_status", upstream.Status)
+}
+
+// firstNonZero returns the first non-zero value.
+func firstNonZero(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
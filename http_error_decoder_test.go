@@ -0,0 +1,166 @@
+package kit_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestDecodeHTTPError(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected *kit.HTTPError
+	}{
+		{
+			name:   "Legacy format with structured details",
+			status: http.StatusBadRequest,
+			body: `{"error":{"code":"request-validation","message":"validation failed","status_code":400,
+				"details":[{"field":"items[2].cpf","tag":"required","message":"CPF é um campo obrigatório"}]}}`,
+			expected: &kit.HTTPError{
+				Slug:    "request-validation",
+				Message: "validation failed",
+				Status:  http.StatusBadRequest,
+				Details: []kit.Violation{{Field: "items[2].cpf", Tag: "required", Message: "CPF é um campo obrigatório"}},
+			},
+		},
+		{
+			name:   "Legacy format with string details",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"code":"request-validation","message":"validation failed","status_code":400,"details":["Nome é um campo obrigatório"]}}`,
+			expected: &kit.HTTPError{
+				Slug:    "request-validation",
+				Message: "validation failed",
+				Status:  http.StatusBadRequest,
+				Details: []kit.Violation{{Message: "Nome é um campo obrigatório"}},
+			},
+		},
+		{
+			name:   "Problem details format",
+			status: http.StatusTooManyRequests,
+			body:   `{"type":"urn:problem-type:too-many-requests","title":"Too Many Requests","status":429,"detail":"rate limit exceeded","code":"too-many-requests","metadata":{"retry_after":30}}`,
+			expected: &kit.HTTPError{
+				Slug:     "too-many-requests",
+				Message:  "rate limit exceeded",
+				Status:   http.StatusTooManyRequests,
+				Metadata: map[string]any{"retry_after": float64(30)},
+			},
+		},
+		{
+			name:   "Status missing from the response falls back to the body",
+			status: 0,
+			body:   `{"error":{"code":"user-not-found","message":"user not found","status_code":404}}`,
+			expected: &kit.HTTPError{
+				Slug:    "user-not-found",
+				Message: "user not found",
+				Status:  http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, kit.DecodeHTTPError(tt.status, []byte(tt.body)))
+		})
+	}
+}
+
+func TestDecodeHTTPErrorUnknownFormat(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		body            string
+		expectedMessage string
+	}{
+		{
+			name:            "Plain text body",
+			status:          http.StatusBadGateway,
+			body:            "upstream connect error\n",
+			expectedMessage: "unexpected error response: upstream connect error",
+		},
+		{
+			name:            "Empty body",
+			status:          http.StatusServiceUnavailable,
+			body:            "",
+			expectedMessage: "unexpected error response: Service Unavailable",
+		},
+		{
+			name:            "JSON body of another format",
+			status:          http.StatusNotFound,
+			body:            `{"message":"not found"}`,
+			expectedMessage: `unexpected error response: {"message":"not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpError := kit.DecodeHTTPError(tt.status, []byte(tt.body))
+			assert.Equal(t, "unknown-error", httpError.Slug, "Slug should match")
+			assert.Equal(t, tt.status, httpError.Status, "HTTP status should match")
+			assert.Equal(t, tt.expectedMessage, httpError.Message, "Error message should match")
+		})
+	}
+}
+
+func TestDecodeHTTPResponse(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Retry-After", "10")
+	recorder.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = recorder.WriteString(`{"error":{"code":"service-unavailable","message":"maintenance","status_code":503}}`)
+
+	resp := recorder.Result()
+	defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+	httpError := kit.DecodeHTTPResponse(resp)
+	assert.Equal(t, "service-unavailable", httpError.Slug)
+	assert.Equal(t, http.StatusServiceUnavailable, httpError.Status)
+	assert.Equal(t, "maintenance", httpError.Message)
+	assert.Equal(t, "10", httpError.Header.Get("Retry-After"))
+
+	failing := &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(failingReader{})}
+	httpError = kit.DecodeHTTPResponse(failing)
+	assert.Equal(t, http.StatusBadGateway, httpError.Status)
+	assert.True(t, strings.HasPrefix(httpError.Message, "reading error response"))
+}
+
+func TestDecodeFastHTTPResponse(t *testing.T) {
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	resp.SetStatusCode(http.StatusTooManyRequests)
+	resp.Header.Set("Retry-After", "5")
+	resp.SetBodyString(`{"error":{"code":"too-many-requests","message":"slow down","status_code":429}}`)
+
+	httpError := kit.DecodeFastHTTPResponse(resp)
+	assert.Equal(t, "too-many-requests", httpError.Slug)
+	assert.Equal(t, http.StatusTooManyRequests, httpError.Status)
+	assert.Equal(t, "5", httpError.Header.Get("Retry-After"))
+}
+
+func TestUpstreamError(t *testing.T) {
+	upstream := kit.DecodeHTTPError(http.StatusNotFound, []byte(`{"error":{"code":"user-not-found","message":"user not found","status_code":404}}`))
+
+	httpError := kit.UpstreamError(upstream)
+	assert.Equal(t, "upstream-error", httpError.Slug)
+	assert.Equal(t, http.StatusBadGateway, httpError.Status)
+	assert.Equal(t, "upstream error [user-not-found]: user not found", httpError.Message)
+	assert.Equal(t, map[string]any{"upstream_code": "user-not-found", "upstream_status": http.StatusNotFound}, httpError.Metadata)
+
+	var cause *kit.HTTPError
+	require.True(t, errors.As(httpError.Unwrap(), &cause), "The upstream error should be kept as the cause")
+	assert.Equal(t, upstream, cause)
+}
+
+// failingReader is a reader that always fails.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
@@ -1,11 +1,17 @@
 package kit_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewHTTPError(t *testing.T) {
@@ -59,7 +65,7 @@ func TestNewHTTPError(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, httpError.Status, "HTTP status should match")
 			assert.Equal(t, tt.expectedSlug, httpError.Slug, "Slug should match")
 			assert.Equal(t, tt.expectedMessage, httpError.Message, "Error message should match")
-			assert.Equal(t, tt.expectedDetails, httpError.Details, "Details should match (if any)")
+			assert.Equal(t, tt.expectedDetails, httpError.DetailMessages(), "Details should match (if any)")
 		})
 	}
 }
@@ -76,7 +82,7 @@ func TestHTTPErrorString(t *testing.T) {
 				Slug:    "validation-error",
 				Status:  400,
 				Message: "Validation error occurred",
-				Details: []string{"field1 is invalid", "field2 is required"},
+				Details: []kit.Violation{{Message: "field1 is invalid"}, {Message: "field2 is required"}},
 			},
 			expectedString: "[validation-error] Validation error occurred (field1 is invalid,field2 is required)",
 		},
@@ -147,3 +153,224 @@ func TestPredefinedHTTPErrorFuncs(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPErrorUnwrap(t *testing.T) {
+	errSentinel := errors.New("sentinel")
+	httpError := kit.HTTPNotFoundError("user-not-found", fmt.Errorf("finding user: %w", errSentinel))
+
+	assert.ErrorIs(t, httpError, errSentinel, "The cause chain should be preserved")
+
+	var validationErrs *kit.ValidationErrors
+	assert.ErrorAs(t, kit.HTTPBadRequestError("request-validation", kit.NewValidationErrors("validation failed")), &validationErrs)
+
+	assert.Nil(t, kit.HTTPInternalServerError(nil).Unwrap(), "No cause when no error is given")
+}
+
+func TestHTTPErrorStackTrace(t *testing.T) {
+	httpError := kit.HTTPInternalServerError(errors.New("internal issue"))
+	assert.Nil(t, httpError.StackTrace(), "Stack trace is not captured by default")
+
+	httpError = httpError.WithStack()
+	require.NotEmpty(t, httpError.StackTrace())
+	assert.Contains(t, httpError.StackTrace()[0], "kit_test.TestHTTPErrorStackTrace", "Stack trace should start at the caller")
+
+	kit.CaptureStackTrace = true
+	defer func() { kit.CaptureStackTrace = false }()
+
+	httpError = kit.HTTPNotFoundError("user-not-found", errors.New("user not found"))
+	require.NotEmpty(t, httpError.StackTrace())
+	assert.Contains(t, httpError.StackTrace()[0], "kit_test.TestHTTPErrorStackTrace", "Stack trace should skip kit constructors")
+
+	data, err := json.Marshal(httpError)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code":"user-not-found","message":"user not found","status_code":404}`, string(data),
+		"Cause and stack trace should never be serialized")
+}
+
+func TestHTTPErrorConstructorsWithHeaders(t *testing.T) {
+	tests := []struct {
+		name             string
+		httpError        *kit.HTTPError
+		expectedSlug     string
+		expectedStatus   int
+		expectedHeader   http.Header
+		expectedMetadata map[string]any
+	}{
+		{
+			name:           "HTTPMethodNotAllowedError lists the allowed methods",
+			httpError:      kit.HTTPMethodNotAllowedError(nil, http.MethodGet, http.MethodPost),
+			expectedSlug:   "method-not-allowed",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedHeader: http.Header{"Allow": {"GET, POST"}},
+		},
+		{
+			name:           "HTTPRequestTimeoutError creates error with proper fields",
+			httpError:      kit.HTTPRequestTimeoutError(nil),
+			expectedSlug:   "request-timeout",
+			expectedStatus: http.StatusRequestTimeout,
+		},
+		{
+			name:           "HTTPRequestEntityTooLargeError creates error with proper fields",
+			httpError:      kit.HTTPRequestEntityTooLargeError(nil),
+			expectedSlug:   "request-too-large",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "HTTPUnsupportedMediaTypeError creates error with proper fields",
+			httpError:      kit.HTTPUnsupportedMediaTypeError(nil),
+			expectedSlug:   "unsupported-media-type",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:             "HTTPTooManyRequestsError sets Retry-After rounded up to seconds",
+			httpError:        kit.HTTPTooManyRequestsError(1500*time.Millisecond, nil),
+			expectedSlug:     "too-many-requests",
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedHeader:   http.Header{"Retry-After": {"2"}},
+			expectedMetadata: map[string]any{"retry_after": 2},
+		},
+		{
+			name:           "HTTPBadGatewayError creates error with proper fields",
+			httpError:      kit.HTTPBadGatewayError(nil),
+			expectedSlug:   "upstream-error",
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "HTTPServiceUnavailableError without retry delay",
+			httpError:      kit.HTTPServiceUnavailableError(0, nil),
+			expectedSlug:   "service-unavailable",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "HTTPGatewayTimeoutError creates error with proper fields",
+			httpError:      kit.HTTPGatewayTimeoutError(nil),
+			expectedSlug:   "timeout",
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:           "HTTPUnauthorizedError with WWW-Authenticate",
+			httpError:      kit.HTTPUnauthorizedError(nil).WithHeader("WWW-Authenticate", `Bearer realm="api"`),
+			expectedSlug:   "unauthorized",
+			expectedStatus: http.StatusUnauthorized,
+			expectedHeader: http.Header{"Www-Authenticate": {`Bearer realm="api"`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedSlug, tt.httpError.Slug, "Slug should match")
+			assert.Equal(t, tt.expectedStatus, tt.httpError.Status, "HTTP status should match")
+			assert.Equal(t, tt.expectedHeader, tt.httpError.Header, "Header should match")
+			assert.Equal(t, tt.expectedMetadata, tt.httpError.Metadata, "Metadata should match")
+		})
+	}
+}
+
+func TestHTTPErrorWithMethodsCopy(t *testing.T) {
+	sentinel := kit.HTTPServiceUnavailableError(0, nil).WithMetadata("region", "sa-east-1")
+
+	e := sentinel.WithRetryAfter(30*time.Second).WithHeader("X-Upstream", "billing").WithMetadata("limit", 100).WithStack()
+
+	assert.Equal(t, http.Header{"Retry-After": {"30"}, "X-Upstream": {"billing"}}, e.Header)
+	assert.Equal(t, map[string]any{"region": "sa-east-1", "retry_after": 30, "limit": 100}, e.Metadata)
+	assert.NotEmpty(t, e.StackTrace())
+	assert.ErrorIs(t, e, sentinel)
+
+	assert.Nil(t, sentinel.Header, "The sentinel headers should not be modified")
+	assert.Equal(t, map[string]any{"region": "sa-east-1"}, sentinel.Metadata, "The sentinel metadata should not be modified")
+	assert.Nil(t, sentinel.StackTrace(), "The sentinel stack trace should not be captured")
+}
+
+var errUserNotFound = kit.HTTPNotFoundError("user-not-found", nil)
+
+func TestHTTPErrorIs(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		target         error
+		expectMatching bool
+	}{
+		{
+			name:           "Same slug with a different message",
+			err:            kit.HTTPNotFoundError("user-not-found", errors.New("user 10 not found")),
+			target:         errUserNotFound,
+			expectMatching: true,
+		},
+		{
+			name:           "Same slug wrapped in the chain",
+			err:            fmt.Errorf("loading profile: %w", kit.HTTPNotFoundError("user-not-found", nil)),
+			target:         errUserNotFound,
+			expectMatching: true,
+		},
+		{
+			name:           "Different slug",
+			err:            kit.HTTPNotFoundError("company-not-found", nil),
+			target:         errUserNotFound,
+			expectMatching: false,
+		},
+		{
+			name:           "Same slug with a different status",
+			err:            kit.HTTPConflictError("user-not-found", nil),
+			target:         errUserNotFound,
+			expectMatching: false,
+		},
+		{
+			name:           "Target without slug",
+			err:            kit.HTTPNotFoundError("user-not-found", nil),
+			target:         &kit.HTTPError{Status: 404},
+			expectMatching: false,
+		},
+		{
+			name:           "Non-HTTPError target",
+			err:            kit.HTTPNotFoundError("user-not-found", nil),
+			target:         errors.New("user-not-found"),
+			expectMatching: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectMatching, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func TestIsClientAndServerError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectClient bool
+		expectServer bool
+	}{
+		{
+			name:         "Wrapped 4xx HTTPError",
+			err:          fmt.Errorf("loading profile: %w", kit.HTTPNotFoundError("user-not-found", nil)),
+			expectClient: true,
+		},
+		{
+			name:         "5xx HTTPError",
+			err:          kit.HTTPInternalServerError(errors.New("boom")),
+			expectServer: true,
+		},
+		{
+			name:         "fiber.Error",
+			err:          fiber.ErrServiceUnavailable,
+			expectServer: true,
+		},
+		{
+			name: "Plain error",
+			err:  errors.New("boom"),
+		},
+		{
+			name: "Nil error",
+			err:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectClient, kit.IsClientError(tt.err), "IsClientError should match")
+			assert.Equal(t, tt.expectServer, kit.IsServerError(tt.err), "IsServerError should match")
+		})
+	}
+}
@@ -0,0 +1,553 @@
+// Package kit provides utilities for handling HTTP request parsing and validation.
+// This file defines the strict JSON body decoder used by ParseRequestBodyWithConfig, which rejects unknown
+// fields, duplicate keys, trailing data and deep nesting, and turns decoder errors into translated,
+// field-aware violations.
+
+package kit
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Tags of the violations reported by the JSON body decoder.
+const (
+	TagJSONSyntax       = "json_syntax"
+	TagJSONType         = "json_type"
+	TagJSONUnknownField = "json_unknown_field"
+	TagJSONDuplicateKey = "json_duplicate_key"
+	TagJSONTrailingData = "json_trailing_data"
+	TagJSONMaxDepth     = "json_max_depth"
+)
+
+// JSON value kinds, used as the params of json_type violations.
+const (
+	jsonString  = "string"
+	jsonNumber  = "number"
+	jsonInteger = "integer"
+	jsonBoolean = "boolean"
+	jsonObject  = "object"
+	jsonArray   = "array"
+)
+
+// jsonBodyMessages are the message templates of the JSON body decoder violations per locale.
+// Templates receive the first param as {0} and the second as {1}; the field path is prepended by jsonViolation.
+var jsonBodyMessages = map[string]map[string]string{
+	TagJSONSyntax: {
+		LocalePtBR: "JSON inválido na posição {0}",
+		LocaleEn:   "invalid JSON at offset {0}",
+		LocaleEs:   "JSON inválido en la posición {0}",
+	},
+	TagJSONType: {
+		LocalePtBR: "esperado {0}, recebido {1}",
+		LocaleEn:   "expected {0}, got {1}",
+		LocaleEs:   "se esperaba {0}, se recibió {1}",
+	},
+	TagJSONUnknownField: {
+		LocalePtBR: "campo desconhecido",
+		LocaleEn:   "unknown field",
+		LocaleEs:   "campo desconocido",
+	},
+	TagJSONDuplicateKey: {
+		LocalePtBR: "chave duplicada",
+		LocaleEn:   "duplicate key",
+		LocaleEs:   "clave duplicada",
+	},
+	TagJSONTrailingData: {
+		LocalePtBR: "dados adicionais após o JSON",
+		LocaleEn:   "unexpected data after the JSON value",
+		LocaleEs:   "datos adicionales después del JSON",
+	},
+	TagJSONMaxDepth: {
+		LocalePtBR: "profundidade máxima de {0} níveis excedida",
+		LocaleEn:   "maximum depth of {0} levels exceeded",
+		LocaleEs:   "profundidad máxima de {0} niveles excedida",
+	},
+}
+
+// jsonKindNames are the names of the JSON value kinds per locale.
+var jsonKindNames = map[string]map[string]string{
+	jsonString:  {LocalePtBR: "texto", LocaleEn: "string", LocaleEs: "texto"},
+	jsonNumber:  {LocalePtBR: "número", LocaleEn: "number", LocaleEs: "número"},
+	jsonInteger: {LocalePtBR: "número inteiro", LocaleEn: "integer", LocaleEs: "número entero"},
+	jsonBoolean: {LocalePtBR: "booleano", LocaleEn: "boolean", LocaleEs: "booleano"},
+	jsonObject:  {LocalePtBR: "objeto", LocaleEn: "object", LocaleEs: "objeto"},
+	jsonArray:   {LocalePtBR: "lista", LocaleEn: "array", LocaleEs: "lista"},
+}
+
+// errBodyRejected stops checking the JSON body once a violation prevents reading the rest of it.
+var errBodyRejected = errors.New("body rejected")
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// jsonFieldCache caches the JSON fields of the struct types checked by jsonChecker.
+var jsonFieldCache sync.Map // map[reflect.Type][]jsonField
+
+// jsonField is a field of a struct as encoding/json decodes it.
+type jsonField struct {
+	name   string
+	typ    reflect.Type
+	index  []int // the index sequence of the field, through the embedded structs promoting it
+	tagged bool  // whether the name comes from the json tag
+}
+
+// decodeJSONBody decodes the JSON body into out with encoding/json, returning a bad-input HTTPError with the
+// violations, translated to the locale, when the body is rejected. The depth, duplicate key and unknown field
+// rules of the config are checked in a token pass before decoding, and data after the JSON value is always rejected.
+func decodeJSONBody(body []byte, out any, config BodyConfig, locale string) error {
+	if config.MaxDepth > 0 || config.DisallowDuplicateKeys || config.DisallowUnknownFields {
+		if violations := checkJSONBody(body, reflect.TypeOf(out), config, locale); len(violations) > 0 {
+			return badInputError(locale, violations)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	err := dec.Decode(out)
+	if err == nil {
+		if rest := bytes.TrimLeft(body[dec.InputOffset():], " \t\r\n"); len(rest) > 0 {
+			return badInputError(locale, []Violation{jsonViolation(locale, TagJSONTrailingData, "")})
+		}
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return badInputError(locale, []Violation{syntaxViolation(locale, syntaxErr.Offset)})
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return badInputError(locale, []Violation{syntaxViolation(locale, int64(len(body)))})
+	case errors.As(err, &typeErr):
+		received, _, _ := strings.Cut(typeErr.Value, " ")
+		if received == "bool" {
+			received = jsonBoolean
+		}
+		expected := jsonKind(typeErr.Type)
+		if expected == "" { // e.g. an interface with methods, which encoding/json cannot decode into
+			return CodeBadInput.New(err)
+		}
+		if expected == jsonNumber && received == jsonNumber && isIntegerKind(typeErr.Type.Kind()) {
+			expected = jsonInteger // fractional or out of range number
+		}
+		path, located := locateTypeError(body, reflect.TypeOf(out), typeErr)
+		if !located {
+			path = jsonErrorPath("", reflect.TypeOf(out), typeErr.Field)
+		}
+		return badInputError(locale, []Violation{jsonViolation(locale, TagJSONType, path, expected, received)})
+	}
+	return CodeBadInput.New(err)
+}
+
+// badInputError creates a bad-input HTTPError with the violations of the body.
+func badInputError(locale string, violations []Violation) *HTTPError {
+	validationErrs := NewValidationErrors(CodeBadInput.Message(locale))
+	validationErrs.AddViolations(violations...)
+	return CodeBadInput.New(validationErrs)
+}
+
+// syntaxViolation creates a json_syntax violation at the offset, translated to the locale.
+func syntaxViolation(locale string, offset int64) Violation {
+	return jsonViolation(locale, TagJSONSyntax, "", strconv.FormatInt(offset, 10))
+}
+
+// jsonViolation creates a violation of the tag at the path, translated to the locale.
+func jsonViolation(locale, tag, path string, params ...string) Violation {
+	message := localizedMessage(jsonBodyMessages[tag], locale)
+	for i, param := range params {
+		if names, ok := jsonKindNames[param]; ok && tag == TagJSONType {
+			param = localizedMessage(names, locale)
+		}
+		message = strings.ReplaceAll(message, "{"+strconv.Itoa(i)+"}", param)
+	}
+	if path != "" {
+		message = path + ": " + message
+	}
+
+	var param string
+	if len(params) > 0 {
+		param = params[0]
+	}
+
+	return Violation{
+		Field:   path,
+		Source:  SourceBody,
+		Tag:     tag,
+		Param:   param,
+		Message: message,
+	}
+}
+
+// checkJSONBody walks the tokens of the first JSON value of the body along the type t it is decoded into,
+// returning the violations of the depth, duplicate key and unknown field rules of the config.
+func checkJSONBody(body []byte, t reflect.Type, config BodyConfig, locale string) []Violation {
+	checker := &jsonChecker{
+		dec:    json.NewDecoder(bytes.NewReader(body)),
+		config: config,
+		locale: locale,
+	}
+	checker.dec.UseNumber()
+
+	if err := checker.value(t, "", 0); err != nil && !errors.Is(err, errBodyRejected) {
+		offset := checker.dec.InputOffset()
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset
+		}
+		checker.violations = append(checker.violations, syntaxViolation(locale, offset))
+	}
+	return checker.violations
+}
+
+// locateTypeError returns the path of a type error returned by the UnmarshalJSON method of a value of the body,
+// such as an Optional field, and whether one returned it: encoding/json returns the errors of these methods
+// verbatim, without the path of the value they decode.
+func locateTypeError(body []byte, t reflect.Type, typeErr *json.UnmarshalTypeError) (string, bool) {
+	checker := &jsonChecker{
+		dec:     json.NewDecoder(bytes.NewReader(body)),
+		body:    body,
+		typeErr: typeErr,
+	}
+	checker.dec.UseNumber()
+
+	_ = checker.value(t, "", 0)
+	return checker.errPath, checker.located
+}
+
+// jsonChecker walks the tokens of a JSON document along the Go type it is decoded into,
+// collecting the violations of the depth, duplicate key and unknown field rules of a BodyConfig,
+// or locating the value returning a type error.
+type jsonChecker struct {
+	dec        *json.Decoder
+	config     BodyConfig
+	locale     string
+	violations []Violation
+
+	body    []byte
+	typeErr *json.UnmarshalTypeError // the type error to locate
+	errPath string                   // the path of the type error, once located
+	located bool
+}
+
+// value walks the next JSON value, decoded into t at the path. A nil t accepts any value.
+func (c *jsonChecker) value(t reflect.Type, path string, depth int) error {
+	start := c.dec.InputOffset()
+	tok, err := c.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := tok.(json.Delim); ok {
+		if c.config.MaxDepth > 0 && depth+1 > c.config.MaxDepth {
+			c.report(TagJSONMaxDepth, path, strconv.Itoa(c.config.MaxDepth))
+			return errBodyRejected
+		}
+
+		if delim == '{' {
+			err = c.object(decodedType(t), path, depth+1)
+		} else {
+			err = c.array(decodedType(t), path, depth+1)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.typeErr != nil {
+		if c.locate(t, path, start) {
+			return errBodyRejected
+		}
+	}
+	return nil
+}
+
+// locate reports whether the value read since the start offset, decoded into t at the path by its UnmarshalJSON
+// method, returns the type error of the checker, recording its path. Values nested in it were checked first.
+func (c *jsonChecker) locate(t reflect.Type, path string, start int64) bool {
+	t = indirectType(t)
+	if t == nil || !reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return false
+	}
+
+	raw := bytes.TrimLeft(c.body[start:c.dec.InputOffset()], " \t\r\n:,")
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(raw, reflect.New(t).Interface()); errors.As(err, &typeErr) &&
+		typeErr.Value == c.typeErr.Value && typeErr.Type == c.typeErr.Type && typeErr.Offset == c.typeErr.Offset {
+		c.errPath = jsonErrorPath(path, unwrapOptional(t), typeErr.Field)
+		c.located = true
+	}
+	return c.located
+}
+
+// object walks the members of a JSON object whose opening delimiter was already read.
+func (c *jsonChecker) object(t reflect.Type, path string, depth int) error {
+	var seen map[string]struct{}
+	if c.config.DisallowDuplicateKeys {
+		seen = map[string]struct{}{}
+	}
+
+	for c.dec.More() {
+		tok, err := c.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		keyPath := joinJSONPath(path, key)
+
+		if seen != nil {
+			if _, duplicate := seen[key]; duplicate {
+				c.report(TagJSONDuplicateKey, keyPath)
+			}
+			seen[key] = struct{}{}
+		}
+
+		memberType, known := memberType(t, key)
+		if !known && c.config.DisallowUnknownFields {
+			c.report(TagJSONUnknownField, keyPath)
+		}
+
+		if err := c.value(memberType, keyPath, depth); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.dec.Token() // closing delimiter
+	return err
+}
+
+// array walks the elements of a JSON array whose opening delimiter was already read.
+func (c *jsonChecker) array(t reflect.Type, path string, depth int) error {
+	var elemType reflect.Type
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		elemType = t.Elem()
+	}
+
+	for i := 0; c.dec.More(); i++ {
+		if err := c.value(elemType, path+"["+strconv.Itoa(i)+"]", depth); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.dec.Token() // closing delimiter
+	return err
+}
+
+// report adds a violation of the tag at the path, translated to the locale of the checker.
+func (c *jsonChecker) report(tag, path string, params ...string) {
+	c.violations = append(c.violations, jsonViolation(c.locale, tag, path, params...))
+}
+
+// decodedType dereferences pointers and Optional types, and returns nil for types decoding themselves or
+// decoded as interfaces, whose members are not checked.
+func decodedType(t reflect.Type) reflect.Type {
+	t = valueType(t)
+	if t == nil || t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	pt := reflect.PointerTo(t)
+	if pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return nil
+	}
+	return t
+}
+
+// jsonKind returns the kind of JSON value decoded into t, or an empty string if any kind is accepted.
+func jsonKind(t reflect.Type) string {
+	t = indirectType(t)
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return jsonString
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return jsonString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return jsonNumber
+	case reflect.Bool:
+		return jsonBoolean
+	case reflect.Struct, reflect.Map:
+		return jsonObject
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return jsonString // []byte is decoded from a base64 string
+		}
+		return jsonArray
+	case reflect.Array:
+		return jsonArray
+	default:
+		return ""
+	}
+}
+
+// isIntegerKind reports whether the kind is a signed or unsigned integer.
+func isIntegerKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uintptr
+}
+
+// jsonErrorPath appends the field of a decoding error, the dotted path encoding/json reports from the type t,
+// to the path, writing array indexes in brackets, e.g. "tags.0" as "tags[0]".
+func jsonErrorPath(path string, t reflect.Type, field string) string {
+	if field == "" {
+		return path
+	}
+
+	for _, segment := range strings.Split(field, ".") {
+		if t = valueType(t); t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+			if _, err := strconv.Atoi(segment); err == nil {
+				path += "[" + segment + "]"
+				continue
+			}
+			t = valueType(t)
+		}
+		path = joinJSONPath(path, segment)
+		t, _ = memberType(t, segment)
+	}
+	return path
+}
+
+// memberType returns the type a member of a JSON object with the key is decoded into,
+// and whether the object type declares it. Members of maps and of unknown types are always declared.
+func memberType(t reflect.Type, key string) (reflect.Type, bool) {
+	switch {
+	case t == nil:
+		return nil, true
+	case t.Kind() == reflect.Map:
+		return t.Elem(), true
+	case t.Kind() != reflect.Struct:
+		return nil, true
+	}
+
+	fields := jsonFields(t)
+	for _, f := range fields {
+		if f.name == key {
+			return f.typ, true
+		}
+	}
+	for _, f := range fields { // encoding/json falls back to a case-insensitive match
+		if strings.EqualFold(f.name, key) {
+			return f.typ, true
+		}
+	}
+	return nil, false
+}
+
+// jsonFields returns the fields of the struct type as encoding/json decodes them, in index order, including the
+// fields promoted from embedded structs. As with encoding/json, a name declared at several depths belongs to the
+// shallowest field, tagged fields win over untagged ones at the same depth, and other conflicting names are dropped.
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+
+	var fields []jsonField
+	current, next := []jsonField{}, []jsonField{{typ: t}}
+	count, nextCount := map[reflect.Type]int{}, map[reflect.Type]int{t: 1}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, embedded := range current {
+			if visited[embedded.typ] {
+				continue
+			}
+			visited[embedded.typ] = true
+
+			for i := range embedded.typ.NumField() {
+				field := embedded.typ.Field(i)
+				if field.Anonymous {
+					if !field.IsExported() && indirectType(field.Type).Kind() != reflect.Struct {
+						continue
+					}
+				} else if !field.IsExported() {
+					continue
+				}
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+
+				name, _, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(embedded.index), i)
+				ft := field.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !field.Anonymous || ft.Kind() != reflect.Struct {
+					f := jsonField{name: name, typ: field.Type, index: index, tagged: name != ""}
+					if name == "" {
+						f.name = field.Name
+					}
+					fields = append(fields, f)
+					if count[embedded.typ] > 1 {
+						// a struct embedded twice at the same depth annihilates its own fields
+						fields = append(fields, f)
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, jsonField{typ: ft, index: index})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(fields, func(a, b jsonField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if j-i == 1 || len(fields[i].index) < len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			dominant = append(dominant, fields[i])
+		}
+		i = j
+	}
+	slices.SortFunc(dominant, func(a, b jsonField) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	jsonFieldCache.Store(t, dominant)
+	return dominant
+}
+
+// joinJSONPath appends the key of an object member to the JSON path.
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,444 @@
+package kit_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type jsonBodyAddress struct {
+	City string `json:"city"`
+}
+
+type jsonBodyAudit struct {
+	Source string `json:"source"`
+}
+
+type jsonBodyInput struct {
+	jsonBodyAudit
+	Name      string            `json:"nome" form:"nome" validate:"required" custom:"Nome"`
+	Age       int               `json:"idade"`
+	Active    bool              `json:"ativo"`
+	Score     float64           `json:"score,string"`
+	Tags      []string          `json:"tags"`
+	Address   *jsonBodyAddress  `json:"endereco"`
+	Extra     map[string]any    `json:"extra"`
+	Payload   []byte            `json:"payload"`
+	Birth     time.Time         `json:"nascimento"`
+	Any       any               `json:"any"`
+	Labels    map[string]string `json:"labels"`
+	Ignored   string            `json:"-"`
+	Untagged  string
+	unexposed string
+}
+
+func TestParseRequestBodyWithConfig(t *testing.T) {
+	strict := kit.BodyConfig{
+		RequireJSON:           true,
+		MaxBodySize:           1024,
+		MaxDepth:              3,
+		DisallowUnknownFields: true,
+		DisallowDuplicateKeys: true,
+	}
+
+	tests := []struct {
+		name               string
+		config             kit.BodyConfig
+		contentType        string
+		acceptLanguage     string
+		body               string
+		expectedOutput     jsonBodyInput
+		expectedSlug       string
+		expectedMessage    string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:        "Valid strict body",
+			config:      strict,
+			contentType: "application/json; charset=utf-8",
+			body: `{"nome":"Ana","idade":30,"ativo":true,"score":"9.5","tags":["a"],"endereco":{"city":"SP"},` +
+				`"extra":{"x":{"y":1}},"payload":"aGk=","nascimento":"2000-01-02T00:00:00Z","any":[1],"source":"app","Untagged":"u","UNTAGGED":"v"}`,
+			expectedOutput: jsonBodyInput{
+				jsonBodyAudit: jsonBodyAudit{Source: "app"},
+				Name:          "Ana",
+				Age:           30,
+				Active:        true,
+				Score:         9.5,
+				Tags:          []string{"a"},
+				Address:       &jsonBodyAddress{City: "SP"},
+				Extra:         map[string]any{"x": map[string]any{"y": float64(1)}},
+				Payload:       []byte("hi"),
+				Birth:         time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+				Any:           []any{float64(1)},
+				Untagged:      "v",
+			},
+		},
+		{
+			name:           "Lenient config ignores unknown fields and duplicates",
+			contentType:    fiber.MIMEApplicationJSON,
+			body:           `{"nome":"Ana","nome":"Bia","unknown":1}` + " \r\n",
+			expectedOutput: jsonBodyInput{Name: "Bia"},
+		},
+		{
+			name:            "First type mismatch",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nome":"Ana","idade":"trinta","ativo":1,"tags":"a","endereco":{"city":[]},"labels":{"a":false}}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "idade", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "number", Message: "idade: esperado número, recebido texto"},
+			},
+		},
+		{
+			name:            "Object where a value is expected in English",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			acceptLanguage:  "en",
+			body:            `{"nome":{"first":"Ana"},"tags":[1]}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "invalid request body",
+			expectedViolations: []kit.Violation{
+				{Field: "nome", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "string", Message: "nome: expected string, got object"},
+			},
+		},
+		{
+			name:            "Unknown fields and duplicate keys in Spanish",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			acceptLanguage:  "es",
+			body:            `{"nome":"Ana","nome":"Bia","endereco":{"city":"SP","zip":"1"},"extra":{"free":1}}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "cuerpo de la solicitud inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "nome", Source: kit.SourceBody, Tag: kit.TagJSONDuplicateKey, Message: "nome: clave duplicada"},
+				{Field: "endereco.zip", Source: kit.SourceBody, Tag: kit.TagJSONUnknownField, Message: "endereco.zip: campo desconocido"},
+			},
+		},
+		{
+			name:            "Trailing data",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nome":"Ana"} {"nome":"Bia"}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Source: kit.SourceBody, Tag: kit.TagJSONTrailingData, Message: "dados adicionais após o JSON"},
+			},
+		},
+		{
+			name:            "Trailing data without a config",
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nome":"a"} xx`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Source: kit.SourceBody, Tag: kit.TagJSONTrailingData, Message: "dados adicionais após o JSON"},
+			},
+		},
+		{
+			name:            "Max depth",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"extra":{"a":{"b":{"c":1}}}}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "extra.a.b", Source: kit.SourceBody, Tag: kit.TagJSONMaxDepth, Param: "3", Message: "extra.a.b: profundidade máxima de 3 níveis excedida"},
+			},
+		},
+		{
+			name:            "Syntax error",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nome":"Ana",}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Source: kit.SourceBody, Tag: kit.TagJSONSyntax, Param: "14", Message: "JSON inválido na posição 14"},
+			},
+		},
+		{
+			name:            "Truncated body",
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nome":"Ana"`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Source: kit.SourceBody, Tag: kit.TagJSONSyntax, Param: "13", Message: "JSON inválido na posição 13"},
+			},
+		},
+		{
+			name:            "Fractional number into an integer",
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"idade":1.5}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "idade", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "integer", Message: "idade: esperado número inteiro, recebido número"},
+			},
+		},
+		{
+			name:            "Invalid value of a type decoding itself",
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nascimento":"ontem"}`,
+			expectedSlug:    "bad-input",
+			expectedMessage: `parsing time "ontem" as "2006-01-02T15:04:05Z07:00": cannot parse "ontem" as "2006"`,
+		},
+		{
+			name:            "Body too large",
+			config:          kit.BodyConfig{MaxBodySize: 8},
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"nome":"Ana"}`,
+			expectedSlug:    "request-too-large",
+			expectedMessage: "corpo da requisição muito grande",
+		},
+		{
+			name:            "JSON required",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationForm,
+			body:            `nome=Ana`,
+			expectedSlug:    "unsupported-media-type",
+			expectedMessage: "tipo de mídia não suportado",
+		},
+		{
+			name:           "Other content types are parsed when JSON is not required",
+			contentType:    fiber.MIMEApplicationForm,
+			body:           `nome=Ana`,
+			expectedOutput: jsonBodyInput{Name: "Ana"},
+		},
+		{
+			name:            "Invalid body of other content types",
+			contentType:     "text/plain",
+			body:            `nome=Ana`,
+			expectedSlug:    "bad-input",
+			expectedMessage: "Unprocessable Entity",
+		},
+		{
+			name:           "JSON suffix media type",
+			config:         strict,
+			contentType:    "application/vnd.arvo+json",
+			body:           `{"nome":"Ana"}`,
+			expectedOutput: jsonBodyInput{Name: "Ana"},
+		},
+		{
+			name:            "Validation runs after decoding",
+			config:          strict,
+			contentType:     fiber.MIMEApplicationJSON,
+			body:            `{"idade":30}`,
+			expectedSlug:    "request-validation",
+			expectedMessage: "validation failed",
+			expectedViolations: []kit.Violation{
+				{Field: "nome", Source: kit.SourceBody, Tag: "required", Value: "", Message: "Nome é um campo obrigatório"},
+			},
+		},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			c := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(c)
+
+			c.Request().Header.SetContentType(tt.contentType)
+			c.Request().Header.Set(fiber.HeaderAcceptLanguage, tt.acceptLanguage)
+			c.Request().SetBody([]byte(tt.body))
+
+			var output jsonBodyInput
+			err := kit.ParseRequestBodyWithConfig(&output, c, v, tt.config)
+
+			if tt.expectedSlug == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, output)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug)
+			assert.Equal(t, tt.expectedMessage, httpError.Message)
+			assert.Equal(t, tt.expectedViolations, httpError.Details)
+		})
+	}
+}
+
+type JSONBodyLevel struct {
+	Level string `json:"level"`
+	Code  string `json:"code"`
+	Label string
+	Note  string `json:"note"`
+}
+
+type JSONBodyTier struct {
+	Level string
+	Code  string `json:"code"`
+	Label string `json:"Label"`
+}
+
+type jsonBodyDecoded struct {
+	*JSONBodyLevel
+	JSONBodyTier
+	Named    fmt.Stringer                    `json:"named"`
+	Count    kit.Optional[int]               `json:"count"`
+	Note     kit.Optional[*string]           `json:"note"`
+	Cleared  *int                            `json:"cleared"`
+	Small    uint8                           `json:"small"`
+	Ratio    float32                         `json:"ratio"`
+	Quoted   int                             `json:"quoted,string"`
+	Addr     netip.Addr                      `json:"addr"`
+	Pair     [2]int                          `json:"pair"`
+	Empty    []string                        `json:"empty"`
+	ByID     map[int]string                  `json:"by_id"`
+	Nested   any                             `json:"nested"`
+	Children []kit.Optional[jsonBodyAddress] `json:"children"`
+}
+
+func TestParseRequestBodyWithConfigDecodesAsEncodingJSON(t *testing.T) {
+	bodies := []string{
+		`{"level":"high","count":3,"note":"n","cleared":null,"small":255,"ratio":1.5,"quoted":"12","addr":"10.0.0.1",` +
+			`"pair":[1,2,3],"empty":[],"by_id":{"1":"a","-2":"b"},"nested":{"a":[true,null,"x",1.5]},"children":[{"city":"SP"},null]}`,
+		`{"count":null,"note":null,"pair":[1],"nested":"x"}`,
+		`{"level":"a","Level":"b","Label":"c","note":"d"}`,
+		`{"LEVEL":"a","label":"c"}`,
+		`{}`,
+	}
+
+	v := kit.NewValidator()
+	kit.RegisterOptional[*string](v)
+	kit.RegisterOptional[jsonBodyAddress](v)
+
+	for _, body := range bodies {
+		t.Run(body, func(t *testing.T) {
+			app := fiber.New()
+			c := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(c)
+
+			c.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+			c.Request().SetBody([]byte(body))
+
+			var expected, output jsonBodyDecoded
+			require.NoError(t, json.Unmarshal([]byte(body), &expected))
+			require.NoError(t, kit.ParseRequestBodyWithConfig(&output, c, v, kit.BodyConfig{DisallowUnknownFields: true}))
+			assert.Equal(t, expected, output)
+		})
+	}
+}
+
+func TestParseRequestBodyWithConfigDecodingErrors(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             kit.BodyConfig
+		body               string
+		expectedMessage    string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:            "Out of range integer",
+			body:            `{"small":256,"ratio":1e39}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "small", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "integer", Message: "small: esperado número inteiro, recebido número"},
+			},
+		},
+		{
+			name:            "Out of range float",
+			body:            `{"ratio":1e39}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "ratio", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "number", Message: "ratio: esperado número, recebido número"},
+			},
+		},
+		{
+			name:            "Invalid map key",
+			body:            `{"by_id":{"x":"a"}}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "by_id.x", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "integer", Message: "by_id.x: esperado número inteiro, recebido número"},
+			},
+		},
+		{
+			name:            "Array element",
+			body:            `{"pair":[1,"b"]}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "pair[1]", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "number", Message: "pair[1]: esperado número, recebido texto"},
+			},
+		},
+		{
+			name:            "Value of an Optional field",
+			body:            `{"count":"três"}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "count", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "number", Message: "count: esperado número, recebido texto"},
+			},
+		},
+		{
+			name:            "Value of a type decoding itself from strings",
+			body:            `{"addr":1}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "addr", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "string", Message: "addr: esperado texto, recebido número"},
+			},
+		},
+		{
+			name:            "Member of an element of Optional type",
+			body:            `{"children":[null,{"city":1}]}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "children[1].city", Source: kit.SourceBody, Tag: kit.TagJSONType, Param: "string", Message: "children[1].city: esperado texto, recebido número"},
+			},
+		},
+		{
+			name:            "Interface with methods",
+			body:            `{"named":"x"}`,
+			expectedMessage: "json: cannot unmarshal string into Go struct field jsonBodyDecoded.named of type fmt.Stringer",
+		},
+		{
+			name:            "Names conflicting between embedded structs are unknown",
+			config:          kit.BodyConfig{DisallowUnknownFields: true},
+			body:            `{"code":"x","Label":"y","level":"z","endereco":{}}`,
+			expectedMessage: "corpo da requisição inválido",
+			expectedViolations: []kit.Violation{
+				{Field: "code", Source: kit.SourceBody, Tag: kit.TagJSONUnknownField, Message: "code: campo desconhecido"},
+				{Field: "endereco", Source: kit.SourceBody, Tag: kit.TagJSONUnknownField, Message: "endereco: campo desconhecido"},
+			},
+		},
+		{
+			name:            "Invalid text",
+			body:            `{"addr":"10.0.0"}`,
+			expectedMessage: `ParseAddr("10.0.0"): IPv4 address too short`,
+		},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			c := app.AcquireCtx(&fasthttp.RequestCtx{})
+			defer app.ReleaseCtx(c)
+
+			c.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+			c.Request().SetBody([]byte(tt.body))
+
+			var output jsonBodyDecoded
+			err := kit.ParseRequestBodyWithConfig(&output, c, v, tt.config)
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, "bad-input", httpError.Slug)
+			assert.Equal(t, tt.expectedMessage, httpError.Message)
+			assert.Equal(t, tt.expectedViolations, httpError.Details)
+		})
+	}
+}
@@ -0,0 +1,419 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file generates JSON Schemas (draft 2020-12) of request structs from their `json`, `validate` and `custom` tags,
+// so that clients and API docs share the rules enforced by Validate.
+
+package kit
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema dialect of the schemas generated by JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema, limited to the keywords JSONSchema generates.
+type Schema struct {
+	Schema string             `json:"$schema,omitempty"`
+	Ref    string             `json:"$ref,omitempty"`
+	Defs   map[string]*Schema `json:"$defs,omitempty"`
+	Type   string             `json:"type,omitempty"`
+	Title  string             `json:"title,omitempty"`
+	Format string             `json:"format,omitempty"`
+
+	// Object keywords.
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+
+	// Array keywords.
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	// String keywords.
+	MinLength       *int   `json:"minLength,omitempty"`
+	MaxLength       *int   `json:"maxLength,omitempty"`
+	Pattern         string `json:"pattern,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+
+	// Numeric keywords.
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	Enum  []any `json:"enum,omitempty"`
+	Const any   `json:"const,omitempty"`
+
+	// Composition keywords.
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+}
+
+var (
+	oneOfValueRegex = regexp.MustCompile(`'[^']*'|\S+`)
+
+	timeType = reflect.TypeFor[time.Time]()
+)
+
+// schemaFormats are the `format` of the string validation tags. The Brazilian and healthcare tags
+// are reported with their own names as custom formats.
+var schemaFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"cpf":      "cpf",
+	"cnpj":     "cnpj",
+	"cep":      "cep",
+	"cns":      "cns",
+	"br_phone": "br_phone",
+	"cid10":    "cid10",
+	"tuss":     "tuss",
+	"cbo":      "cbo",
+	"ans":      "ans",
+	"crm":      "crm",
+	"coren":    "coren",
+}
+
+// schemaPatterns are the `pattern` of the string validation tags that restrict the characters of a string.
+var schemaPatterns = map[string]string{
+	"alpha":    `^[a-zA-Z]+$`,
+	"alphanum": `^[a-zA-Z0-9]+$`,
+	"numeric":  `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"number":   `^[0-9]+$`,
+}
+
+// JSONSchema generates the JSON Schema of the request body bound into the struct s, or a pointer to it.
+// Properties are named by their `json` tags and titled by their `custom` labels, and the following `validate` tags
+// become keywords: required, len, min, max, gt, gte, lt, lte, oneof, unique, dive, the string formats (email, url,
+// uuid, ...) and patterns (alpha, alphanum, numeric), and the Brazilian and healthcare tags as custom formats (cpf,
+// cnpj, cid10, ...). Other tags, such as cross-field and conditional ones, are not representable and are ignored,
+// as are fields bound from the query, path params or headers. Optional fields are never listed as required,
+// and accept null unless tagged with required. The constraints of fields tagged with omitempty are wrapped in an
+// anyOf also accepting their empty value. Recursive named structs are referenced from $defs.
+func JSONSchema(s any) (*Schema, error) {
+	t := indirectType(reflect.TypeOf(s))
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("json schema: expected a struct, got %T", s)
+	}
+
+	g := &schemaGenerator{
+		root:       t,
+		building:   map[reflect.Type]bool{},
+		referenced: map[reflect.Type]bool{},
+		defs:       map[string]*Schema{},
+	}
+	schema, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Schema = JSONSchemaDialect
+	if len(g.defs) > 0 {
+		schema.Defs = g.defs
+	}
+	return schema, nil
+}
+
+// schemaGenerator holds the state of the generation of a schema, tracking the structs being generated
+// to reference recursive structs instead of expanding them endlessly.
+type schemaGenerator struct {
+	root       reflect.Type
+	building   map[reflect.Type]bool
+	referenced map[reflect.Type]bool
+	defs       map[string]*Schema
+}
+
+// typeSchema generates the schema of a Go type, before its validation tags are applied.
+func (g *schemaGenerator) typeSchema(t reflect.Type) (*Schema, error) {
+	t = valueType(t)
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: ptr(float64(0))}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &Schema{Type: "string", ContentEncoding: "base64"}, nil // encoding/json encodes []byte as base64
+		}
+		items, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if g.building[t] {
+			g.referenced[t] = true
+			return g.reference(t), nil
+		}
+		schema, err := g.structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		if g.referenced[t] && t != g.root {
+			g.defs[t.Name()] = schema
+			return g.reference(t), nil
+		}
+		return schema, nil
+	}
+
+	return &Schema{}, nil // interfaces and other kinds accept any value
+}
+
+// reference returns the schema referencing a recursive struct: the root schema or its definition in $defs.
+func (g *schemaGenerator) reference(t reflect.Type) *Schema {
+	if t == g.root {
+		return &Schema{Ref: "#"}
+	}
+	return &Schema{Ref: "#/$defs/" + t.Name()}
+}
+
+// structSchema generates the object schema of a struct, flattening embedded structs without a JSON name.
+func (g *schemaGenerator) structSchema(t reflect.Type) (*Schema, error) {
+	g.building[t] = true
+	defer delete(g.building, t)
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	if err := g.addFields(schema, t); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// addFields adds the fields of the struct bound from the body to the properties of the object schema.
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if (!field.IsExported() && !field.Anonymous) || field.Tag.Get("json") == "-" || fieldSource(field) != "" {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "" {
+			if err := g.addFields(schema, indirectType(field.Type)); err != nil { // embedded struct without a name
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		property, err := g.typeSchema(field.Type)
+		if err != nil {
+			return err
+		}
+		if label := field.Tag.Get("custom"); label != "" && property.Ref == "" {
+			property.Title = label
+		}
+
+		required, err := applyRules(property, field.Type, strings.Split(field.Tag.Get("validate"), ","))
+		if err != nil {
+			return fmt.Errorf("json schema: field %s: %w", field.Name, err)
+		}
+		if optional := indirectType(field.Type).Implements(optionalFieldType); !optional && required {
+			schema.Required = append(schema.Required, name)
+		} else if optional && !required { // Optional fields may be absent, and null unless required
+			allowNull(property)
+		}
+		schema.Properties[name] = property
+	}
+	return nil
+}
+
+// applyRules applies the validation tags of a field of type t to its schema, reporting whether the field is required.
+// Tags following dive are applied to the schema of the elements. With omitempty, the schema also accepts the empty
+// value the tags are not checked against.
+func applyRules(schema *Schema, t reflect.Type, tags []string) (bool, error) {
+	fieldType, typeSchema := t, *schema
+	t = valueType(t)
+	required, omitEmpty := false, false
+
+	var err error
+tags:
+	for i := 0; i < len(tags); i++ {
+		tag, param, _ := strings.Cut(strings.TrimSpace(tags[i]), "=")
+		if strings.Contains(tag, "|") {
+			continue // or-groups are not representable
+		}
+
+		switch tag {
+		case "omitempty":
+			omitEmpty = true
+		case "required":
+			required = true
+			if t.Kind() == reflect.String && schema.MinLength == nil {
+				schema.MinLength = ptr(1)
+			}
+		case "dive":
+			elem := schema.Items
+			if schema.Type == "object" {
+				elem = schema.AdditionalProperties
+			}
+			if elem != nil && elem.Ref == "" {
+				_, err = applyRules(elem, t.Elem(), tags[i+1:])
+			}
+			break tags
+		case "keys":
+			for i < len(tags) && strings.TrimSpace(tags[i]) != "endkeys" {
+				i++ // map keys are not representable
+			}
+		case "len", "min", "max", "gt", "gte", "lt", "lte":
+			if err := applyBound(schema, tag, param); err != nil {
+				return required, err
+			}
+		case "oneof":
+			for _, value := range oneOfValueRegex.FindAllString(param, -1) {
+				value = strings.Trim(value, "'")
+				if schema.Type == "integer" || schema.Type == "number" {
+					n, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return required, fmt.Errorf("invalid oneof value %q", value)
+					}
+					schema.Enum = append(schema.Enum, n)
+				} else {
+					schema.Enum = append(schema.Enum, value)
+				}
+			}
+		case "unique":
+			schema.UniqueItems = schema.Type == "array"
+		default:
+			if format, ok := schemaFormats[tag]; ok && schema.Type == "string" {
+				schema.Format = format
+			} else if pattern, ok := schemaPatterns[tag]; ok && schema.Type == "string" {
+				schema.Pattern = pattern
+			}
+		}
+	}
+
+	if omitEmpty && !reflect.DeepEqual(*schema, typeSchema) {
+		allowEmpty(schema, fieldType)
+	}
+	return required, err
+}
+
+// allowEmpty wraps the schema of a field of type t in an anyOf also accepting its empty value, which omitempty
+// exempts from the other tags: "", 0 or false, and null for pointers, slices and maps.
+func allowEmpty(schema *Schema, t reflect.Type) {
+	constrained := *schema
+	constrained.Title = ""
+	alternatives := []*Schema{&constrained}
+
+	switch kind := valueType(t).Kind(); {
+	case kind == reflect.String:
+		alternatives = append(alternatives, &Schema{Const: ""})
+	case kind == reflect.Bool:
+		alternatives = append(alternatives, &Schema{Const: false})
+	case isIntegerKind(kind) || kind == reflect.Float32 || kind == reflect.Float64:
+		alternatives = append(alternatives, &Schema{Const: 0})
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		alternatives = append(alternatives, &Schema{Type: "null"})
+	}
+
+	*schema = Schema{Title: schema.Title, AnyOf: alternatives}
+}
+
+// allowNull makes the schema also accept null, adding it to the anyOf of the schema or wrapping the schema in one.
+func allowNull(schema *Schema) {
+	if schema.AnyOf == nil {
+		constrained := *schema
+		constrained.Title = ""
+		*schema = Schema{Title: schema.Title, AnyOf: []*Schema{&constrained}}
+	}
+	for _, alternative := range schema.AnyOf {
+		if alternative.Type == "null" {
+			return
+		}
+	}
+	schema.AnyOf = append(schema.AnyOf, &Schema{Type: "null"})
+}
+
+// applyBound applies a len, min, max, gt, gte, lt or lte tag to the schema: as bounds of the value of numbers,
+// and as bounds of the length of strings, arrays and objects.
+func applyBound(schema *Schema, tag, param string) error {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s param %q", tag, param)
+	}
+
+	if schema.Type == "integer" || schema.Type == "number" {
+		switch tag {
+		case "len":
+			schema.Minimum, schema.Maximum = ptr(n), ptr(n)
+		case "min", "gte":
+			schema.Minimum = ptr(n)
+		case "max", "lte":
+			schema.Maximum = ptr(n)
+		case "gt":
+			schema.ExclusiveMinimum = ptr(n)
+		case "lt":
+			schema.ExclusiveMaximum = ptr(n)
+		}
+		return nil
+	}
+
+	var minLength, maxLength **int
+	switch schema.Type {
+	case "string":
+		minLength, maxLength = &schema.MinLength, &schema.MaxLength
+	case "array":
+		minLength, maxLength = &schema.MinItems, &schema.MaxItems
+	case "object":
+		minLength, maxLength = &schema.MinProperties, &schema.MaxProperties
+	default:
+		return nil
+	}
+
+	length := int(n)
+	switch tag {
+	case "len":
+		*minLength, *maxLength = ptr(length), ptr(length)
+	case "min", "gte":
+		*minLength = ptr(length)
+	case "max", "lte":
+		*maxLength = ptr(length)
+	case "gt":
+		*minLength = ptr(length + 1)
+	case "lt":
+		*maxLength = ptr(length - 1)
+	}
+	return nil
+}
+
+// ptr returns a pointer to the value.
+func ptr[T any](v T) *T {
+	return &v
+}
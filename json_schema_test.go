@@ -0,0 +1,225 @@
+package kit_test
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"createdBy" validate:"required,email"`
+	}
+
+	type Beneficiary struct {
+		Name     string   `json:"name" validate:"required,max=120" custom:"Nome"`
+		CPF      string   `json:"cpf" validate:"required,cpf" custom:"CPF"`
+		Kinship  string   `json:"kinship" validate:"omitempty,oneof=titular 'cônjuge' filho"`
+		Age      int      `json:"age" validate:"gte=0,lt=130"`
+		Phones   []string `json:"phones" validate:"max=3,unique,dive,br_phone"`
+		Nickname *string  `json:"nickname,omitempty" validate:"omitempty,alpha,min=2"`
+		Children int      `json:"children" validate:"omitempty,gte=1" custom:"Filhos"`
+		Notes    string   `json:"notes" validate:"omitempty"`
+	}
+
+	type Contract struct {
+		Audit
+		TenantID      string            `reqHeader:"X-Tenant-ID" validate:"required"`
+		Page          int               `query:"page" validate:"gte=1"`
+		Number        string            `json:"number" validate:"required,len=10,numeric"`
+		Plan          uint8             `json:"plan" validate:"oneof=1 2 3"`
+		Discount      float64           `json:"discount" validate:"gt=0,lte=0.5"`
+		StartsAt      time.Time         `json:"startsAt" validate:"required"`
+		Beneficiaries []Beneficiary     `json:"beneficiaries" validate:"required,min=1,dive" custom:"Beneficiário"`
+		Metadata      map[string]string `json:"metadata" validate:"max=5,dive,keys,min=1,endkeys,max=50"`
+		Signature     []byte            `json:"signature"`
+		Extra         any               `json:"extra"`
+		Contact       string            `json:"contact" validate:"email|cpf"`
+		Internal      string            `json:"-"`
+		internal      string
+	}
+
+	schema, err := kit.JSONSchema(&Contract{})
+	require.NoError(t, err)
+
+	got, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"createdBy": {"type": "string", "format": "email", "minLength": 1},
+			"number": {"type": "string", "minLength": 10, "maxLength": 10, "pattern": "^[-+]?[0-9]+(?:\\.[0-9]+)?$"},
+			"plan": {"type": "integer", "minimum": 0, "enum": [1, 2, 3]},
+			"discount": {"type": "number", "exclusiveMinimum": 0, "maximum": 0.5},
+			"startsAt": {"type": "string", "format": "date-time"},
+			"beneficiaries": {
+				"type": "array",
+				"title": "Beneficiário",
+				"minItems": 1,
+				"items": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string", "title": "Nome", "minLength": 1, "maxLength": 120},
+						"cpf": {"type": "string", "title": "CPF", "format": "cpf", "minLength": 1},
+						"kinship": {"anyOf": [{"type": "string", "enum": ["titular", "cônjuge", "filho"]}, {"const": ""}]},
+						"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 130},
+						"phones": {
+							"type": "array",
+							"maxItems": 3,
+							"uniqueItems": true,
+							"items": {"type": "string", "format": "br_phone"}
+						},
+						"nickname": {
+							"anyOf": [
+								{"type": "string", "pattern": "^[a-zA-Z]+$", "minLength": 2},
+								{"const": ""},
+								{"type": "null"}
+							]
+						},
+						"children": {"title": "Filhos", "anyOf": [{"type": "integer", "minimum": 1}, {"const": 0}]},
+						"notes": {"type": "string"}
+					},
+					"required": ["name", "cpf"]
+				}
+			},
+			"metadata": {
+				"type": "object",
+				"maxProperties": 5,
+				"additionalProperties": {"type": "string", "maxLength": 50}
+			},
+			"signature": {"type": "string", "contentEncoding": "base64"},
+			"extra": {},
+			"contact": {"type": "string"}
+		},
+		"required": ["createdBy", "number", "startsAt", "beneficiaries"]
+	}`, string(got))
+}
+
+func TestJSONSchemaRecursiveStructs(t *testing.T) {
+	type Category struct {
+		Name     string      `json:"name" validate:"required"`
+		Children []*Category `json:"children" validate:"dive"`
+	}
+
+	type Catalog struct {
+		Root  Category  `json:"root"`
+		Next  *Catalog  `json:"next"`
+		Files []*string `json:"files"`
+	}
+
+	schema, err := kit.JSONSchema(Catalog{})
+	require.NoError(t, err)
+
+	got, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"root": {"$ref": "#/$defs/Category"},
+			"next": {"$ref": "#"},
+			"files": {"type": "array", "items": {"type": "string"}}
+		},
+		"$defs": {
+			"Category": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/Category"}}
+				},
+				"required": ["name"]
+			}
+		}
+	}`, string(got))
+}
+
+func TestJSONSchemaFileUploads(t *testing.T) {
+	type Upload struct {
+		Document *multipart.FileHeader `form:"document" validate:"required,file_max_size=5MB"`
+	}
+
+	schema, err := kit.JSONSchema(Upload{})
+	require.NoError(t, err)
+
+	got, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"document": {"type": "string", "format": "binary"}},
+		"required": ["document"]
+	}`, string(got))
+}
+
+func TestJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          any
+		expectedErr string
+	}{
+		{
+			name:        "Not a struct",
+			in:          []string{},
+			expectedErr: "json schema: expected a struct, got []string",
+		},
+		{
+			name:        "Nil",
+			in:          nil,
+			expectedErr: "json schema: expected a struct, got <nil>",
+		},
+		{
+			name: "Invalid bound param",
+			in: struct {
+				Name string `json:"name" validate:"max=ten"`
+			}{},
+			expectedErr: `json schema: field Name: invalid max param "ten"`,
+		},
+		{
+			name: "Invalid numeric oneof value",
+			in: struct {
+				Plan int `json:"plan" validate:"oneof=gold silver"`
+			}{},
+			expectedErr: `json schema: field Plan: invalid oneof value "gold"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := kit.JSONSchema(tt.in)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestJSONSchemaOptionalFields(t *testing.T) {
+	type Patch struct {
+		Name kit.Optional[string] `json:"name" validate:"required,max=10"`
+		Age  kit.Optional[int]    `json:"age" validate:"gte=0"`
+		Nick kit.Optional[string] `json:"nick" validate:"omitempty,min=2" custom:"Apelido"`
+	}
+
+	schema, err := kit.JSONSchema(Patch{})
+	require.NoError(t, err)
+
+	got, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 10},
+			"age": {"anyOf": [{"type": "integer", "minimum": 0}, {"type": "null"}]},
+			"nick": {"title": "Apelido", "anyOf": [{"type": "string", "minLength": 2}, {"const": ""}, {"type": "null"}]}
+		}
+	}`, string(got))
+}
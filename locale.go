@@ -0,0 +1,84 @@
+// Package kit provides utilities for localized messages in Go applications.
+// This file defines the supported locales and the negotiation of the request locale
+// from the Accept-Language header.
+
+package kit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Supported locales for localized messages.
+const (
+	LocalePtBR = "pt_BR"
+	LocaleEn   = "en"
+	LocaleEs   = "es"
+
+	DefaultLocale = LocalePtBR
+)
+
+// SupportedLocales lists the locales with translated messages, in order of preference.
+var SupportedLocales = []string{LocalePtBR, LocaleEn, LocaleEs}
+
+// RequestLocale negotiates the locale of the request from its Accept-Language header, honoring quality values.
+// Language tags are matched by their primary language (e.g. pt-PT and pt match pt_BR, en-US matches en),
+// falling back to DefaultLocale when no supported locale is acceptable.
+func RequestLocale(c *fiber.Ctx) string {
+	return NegotiateLocale(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// NegotiateLocale returns the supported locale that best matches the Accept-Language header value,
+// or DefaultLocale when none matches.
+func NegotiateLocale(acceptLanguage string) string {
+	best, bestQuality := DefaultLocale, 0.0
+
+	for _, spec := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(spec), ";")
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		locale, ok := matchLocale(tag)
+		if ok && quality > bestQuality {
+			best, bestQuality = locale, quality
+		}
+	}
+
+	return best
+}
+
+// matchLocale returns the supported locale with the same primary language as the language tag.
+// The wildcard tag matches the DefaultLocale.
+func matchLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "*" {
+		return DefaultLocale, true
+	}
+
+	language, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	for _, locale := range SupportedLocales {
+		supported, _, _ := strings.Cut(strings.ToLower(locale), "_")
+		if language == supported {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+// localizedMessage returns the message of the locale, falling back to the DefaultLocale message.
+func localizedMessage(messages map[string]string, locale string) string {
+	if msg, ok := messages[locale]; ok {
+		return msg
+	}
+	return messages[DefaultLocale]
+}
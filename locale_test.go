@@ -0,0 +1,46 @@
+package kit_test
+
+import (
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expectedLocale string
+	}{
+		{name: "Empty header falls back to pt_BR", acceptLanguage: "", expectedLocale: kit.LocalePtBR},
+		{name: "Exact pt-BR", acceptLanguage: "pt-BR", expectedLocale: kit.LocalePtBR},
+		{name: "English region", acceptLanguage: "en-US,en;q=0.9", expectedLocale: kit.LocaleEn},
+		{name: "Spanish region", acceptLanguage: "es-AR", expectedLocale: kit.LocaleEs},
+		{name: "Other Portuguese region", acceptLanguage: "pt-PT", expectedLocale: kit.LocalePtBR},
+		{name: "Quality values are honored", acceptLanguage: "en;q=0.5, es;q=0.8", expectedLocale: kit.LocaleEs},
+		{name: "Unsupported languages are skipped", acceptLanguage: "fr-FR, de;q=0.9, en;q=0.1", expectedLocale: kit.LocaleEn},
+		{name: "Only unsupported languages fall back to pt_BR", acceptLanguage: "fr-FR, de", expectedLocale: kit.LocalePtBR},
+		{name: "Zero quality is not acceptable", acceptLanguage: "en;q=0", expectedLocale: kit.LocalePtBR},
+		{name: "Invalid quality is skipped", acceptLanguage: "en;q=abc, es", expectedLocale: kit.LocaleEs},
+		{name: "Wildcard", acceptLanguage: "*", expectedLocale: kit.LocalePtBR},
+		{name: "Underscore separator", acceptLanguage: "en_GB", expectedLocale: kit.LocaleEn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedLocale, kit.NegotiateLocale(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestRequestLocale(t *testing.T) {
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.Set(fiber.HeaderAcceptLanguage, "es-MX,es;q=0.9")
+	assert.Equal(t, kit.LocaleEs, kit.RequestLocale(c))
+}
@@ -81,7 +81,8 @@ func LoggerMiddlewareWithConfig(logger *slog.Logger, config Config) fiber.Handle
 
 		var errmsg string
 
-		err := c.Next()
+		handlerErr := c.Next()
+		err := handlerErr
 		if err != nil {
 			errmsg = err.Error()
 			if err = errHandler(c, err); err != nil {
@@ -182,6 +183,11 @@ func LoggerMiddlewareWithConfig(logger *slog.Logger, config Config) fiber.Handle
 			slog.Attr{Key: "user", Value: slog.GroupValue(userAttributes...)},
 		)
 
+		// error cause chain and stack trace, never exposed to the client
+		if handlerErr != nil {
+			attributes = append(attributes, errorAttr(handlerErr))
+		}
+
 		// custom context values
 		if v := c.Context().UserValue(customAttributesCtxKey); v != nil {
 			switch attrs := v.(type) {
@@ -0,0 +1,147 @@
+// Package kit provides utilities for handling HTTP request parsing and validation.
+// This file defines the normalization step driven by the `normalize` struct tag, which cleans up parsed
+// request values (trimming, case folding, digits only, HTML stripping, Unicode NFC) before validation.
+
+package kit
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	htmlBlockRegex = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(script|style)\s*>`)
+	htmlTagRegex   = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+var (
+	normalizersMu sync.RWMutex
+	normalizers   = map[string]func(string) string{
+		"trim":       strings.TrimSpace,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"digits":     onlyDigits,
+		"collapse":   collapseSpaces,
+		"strip_html": stripHTML,
+		"nfc":        norm.NFC.String,
+	}
+)
+
+// RegisterNormalizer registers a normalizer usable in `normalize` tags, replacing any normalizer with the same name.
+func RegisterNormalizer(name string, fn func(string) string) {
+	normalizersMu.Lock()
+	defer normalizersMu.Unlock()
+
+	normalizers[name] = fn
+}
+
+// Normalize applies the normalizers of the `normalize` tags of the struct pointed by out, e.g.
+// `normalize:"trim,lower"`, in order, to its string fields and to the strings of its slice, array and map fields.
+// Nested structs are normalized recursively, whether tagged or not. The built-in normalizers are trim, lower,
+// upper, digits, collapse (trims and collapses inner whitespace), strip_html and nfc (Unicode NFC).
+// It returns an error if a tag names an unknown normalizer.
+func Normalize(out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("normalize: expected a non-nil pointer, got %T", out)
+	}
+	return normalizeValue(v, nil)
+}
+
+// normalizeValue applies the normalizers to the value, descending into pointers, structs, slices, arrays and maps.
+// Strings are only modified when normalizers apply, i.e. when they are inside a tagged field.
+func normalizeValue(v reflect.Value, fns []func(string) string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return normalizeValue(v.Elem(), fns)
+
+	case reflect.String:
+		if len(fns) > 0 && v.CanSet() {
+			s := v.String()
+			for _, fn := range fns {
+				s = fn(s)
+			}
+			v.SetString(s)
+		}
+
+	case reflect.Struct:
+		if v.Type().Implements(optionalFieldType) {
+			return normalizeValue(v.Field(0), fns) // the tags of Optional fields apply to their Value
+		}
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+
+			fieldFns, err := fieldNormalizers(field)
+			if err != nil {
+				return err
+			}
+			if err := normalizeValue(v.Field(i), fieldFns); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := normalizeValue(v.Index(i), fns); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem() // map values are not addressable
+			elem.Set(iter.Value())
+			if err := normalizeValue(elem, fns); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+
+	return nil
+}
+
+// fieldNormalizers returns the normalizers named by the `normalize` tag of the field.
+func fieldNormalizers(field reflect.StructField) ([]func(string) string, error) {
+	tag := field.Tag.Get("normalize")
+	if tag == "" {
+		return nil, nil
+	}
+
+	normalizersMu.RLock()
+	defer normalizersMu.RUnlock()
+
+	names := strings.Split(tag, ",")
+	fns := make([]func(string) string, 0, len(names))
+	for _, name := range names {
+		fn, ok := normalizers[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("normalize: field %s: unknown normalizer %q", field.Name, name)
+		}
+		fns = append(fns, fn)
+	}
+	return fns, nil
+}
+
+// collapseSpaces trims the string and replaces each run of inner whitespace with a single space.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// stripHTML removes HTML tags from the string, along with the content of script and style elements.
+// HTML entities are kept escaped.
+func stripHTML(s string) string {
+	return htmlTagRegex.ReplaceAllString(htmlBlockRegex.ReplaceAllString(s, ""), "")
+}
@@ -0,0 +1,181 @@
+package kit_test
+
+import (
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	type Phone struct {
+		Number string `normalize:"digits"`
+	}
+
+	type Beneficiary struct {
+		Name   string   `normalize:"collapse"`
+		CPF    *string  `normalize:"digits"`
+		Phones []Phone  `json:"phones"`
+		Tags   []string `normalize:"trim,upper"`
+	}
+
+	type Request struct {
+		Email         string                 `normalize:"trim,lower"`
+		Notes         string                 `normalize:"strip_html,collapse"`
+		Code          string                 `normalize:"nfc"`
+		Raw           string                 // untagged fields are kept as sent
+		Holder        Beneficiary            `json:"holder"`
+		Dependents    []*Beneficiary         `json:"dependents"`
+		Attributes    map[string]string      `normalize:"trim"`
+		ByPlan        map[string]Beneficiary `json:"byPlan"`
+		MissingHolder *Beneficiary           `json:"missingHolder"`
+		unexported    string                 `normalize:"trim"`
+	}
+
+	cpf := "529.982.247-25"
+	dependentCPF := " 111.444.777-35 "
+	req := Request{
+		Email: "  Maria.Silva@Example.COM ",
+		Notes: "<p>Paciente  <b>estável</b></p><script>alert('x')</script> &amp; sem  queixas",
+		Code:  "José",
+		Raw:   "  As Sent  ",
+		Holder: Beneficiary{
+			Name:   "  Maria   da  Silva ",
+			CPF:    &cpf,
+			Phones: []Phone{{Number: "(11) 98765-4321"}},
+			Tags:   []string{" vip ", "titular"},
+		},
+		Dependents: []*Beneficiary{
+			{Name: "João \t Silva", CPF: &dependentCPF},
+			nil,
+		},
+		Attributes: map[string]string{"plan": "  gold  "},
+		ByPlan:     map[string]Beneficiary{"gold": {Name: " Ana  Souza "}},
+		unexported: "  kept  ",
+	}
+
+	require.NoError(t, kit.Normalize(&req))
+
+	assert.Equal(t, "maria.silva@example.com", req.Email)
+	assert.Equal(t, "Paciente estável &amp; sem queixas", req.Notes)
+	assert.Equal(t, "José", req.Code)
+	assert.Equal(t, "  As Sent  ", req.Raw)
+	assert.Equal(t, "Maria da Silva", req.Holder.Name)
+	assert.Equal(t, "52998224725", *req.Holder.CPF)
+	assert.Equal(t, "11987654321", req.Holder.Phones[0].Number)
+	assert.Equal(t, []string{"VIP", "TITULAR"}, req.Holder.Tags)
+	assert.Equal(t, "João Silva", req.Dependents[0].Name)
+	assert.Equal(t, "11144477735", *req.Dependents[0].CPF)
+	assert.Nil(t, req.Dependents[1])
+	assert.Equal(t, map[string]string{"plan": "gold"}, req.Attributes)
+	assert.Equal(t, "Ana Souza", req.ByPlan["gold"].Name)
+	assert.Nil(t, req.MissingHolder)
+	assert.Equal(t, "  kept  ", req.unexported)
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	type Request struct {
+		Name string `normalize:"trim,titlecase"`
+	}
+
+	tests := []struct {
+		name        string
+		out         any
+		expectedErr string
+	}{
+		{
+			name:        "Unknown normalizer",
+			out:         &Request{},
+			expectedErr: `normalize: field Name: unknown normalizer "titlecase"`,
+		},
+		{
+			name:        "Not a pointer",
+			out:         Request{},
+			expectedErr: "normalize: expected a non-nil pointer, got kit_test.Request",
+		},
+		{
+			name:        "Nil pointer",
+			out:         (*Request)(nil),
+			expectedErr: "normalize: expected a non-nil pointer, got *kit_test.Request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, kit.Normalize(tt.out), tt.expectedErr)
+		})
+	}
+}
+
+func TestRegisterNormalizer(t *testing.T) {
+	kit.RegisterNormalizer("test_reverse", func(s string) string {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	})
+
+	req := struct {
+		Name string `normalize:"trim,test_reverse"`
+	}{Name: " abc "}
+
+	require.NoError(t, kit.Normalize(&req))
+	assert.Equal(t, "cba", req.Name)
+}
+
+func TestParseRequestBodyNormalizes(t *testing.T) {
+	type Beneficiary struct {
+		Name string `json:"name" normalize:"collapse" validate:"required"`
+		CPF  string `json:"cpf" normalize:"digits" validate:"required,len=11,cpf"`
+	}
+
+	type Request struct {
+		Email         string        `json:"email" normalize:"trim,lower" validate:"required,email"`
+		Beneficiaries []Beneficiary `json:"beneficiaries" validate:"dive"`
+	}
+
+	v := kit.NewValidator()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedOutput Request
+		expectedSlug   string
+	}{
+		{
+			name: "Normalized before validation",
+			body: `{"email":"  Maria@Example.COM ","beneficiaries":[{"name":" Maria   Silva ","cpf":"529.982.247-25"}]}`,
+			expectedOutput: Request{
+				Email:         "maria@example.com",
+				Beneficiaries: []Beneficiary{{Name: "Maria Silva", CPF: "52998224725"}},
+			},
+		},
+		{
+			name:         "Normalized value still validated",
+			body:         `{"email":"maria@example.com","beneficiaries":[{"name":"   ","cpf":"529.982.247-25"}]}`,
+			expectedSlug: "request-validation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out Request
+			err := parseRoute(t, fiber.MethodPost, "/contracts/1", tt.body, nil, func(c *fiber.Ctx) error {
+				return kit.ParseRequestBody(&out, c, v)
+			})
+
+			if tt.expectedSlug == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug)
+		})
+	}
+}
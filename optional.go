@@ -0,0 +1,256 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines Optional, a field type telling absent, null and provided values apart in partial updates (PATCH),
+// along with its validation, which only checks the fields present in the request.
+
+package kit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Optional is a request field that tells an absent field from an explicit null and from a provided value,
+// for partial updates. Its validation tags apply to its Value and are only checked when the field is present:
+// absent fields are not validated, and null fields only fail `required`, which means "not null if present".
+// Fields of type Optional are validated this way by Validate.StructTranslated and Validate.StructTranslatedLocale,
+// once their type is registered with RegisterOptional.
+type Optional[T any] struct {
+	Value   T    // The provided value, the zero value when absent or null.
+	Present bool // Whether the field was present in the request, even if null.
+	Null    bool // Whether the field was explicitly null.
+}
+
+// Some returns a present Optional holding the value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Present: true}
+}
+
+// Null returns a present Optional explicitly set to null.
+func Null[T any]() Optional[T] {
+	return Optional[T]{Present: true, Null: true}
+}
+
+// Ptr returns a pointer to the value, or nil if the field is absent or null.
+func (o Optional[T]) Ptr() *T {
+	if !o.Present || o.Null {
+		return nil
+	}
+	return &o.Value
+}
+
+// IsZero reports whether the field is absent, so that `json:",omitzero"` omits absent fields.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// MarshalJSON encodes the value, or null if the field is absent or null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present || o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON decodes the value, marking the field as present and, for a JSON null, as null.
+// It is only called for fields present in the JSON document.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	o.Value, o.Present, o.Null = zero, true, false
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// optionalState returns the value of the field and whether it is present and null.
+func (o Optional[T]) optionalState() (any, bool, bool) {
+	return o.Value, o.Present, o.Null
+}
+
+// optionalType returns the type of the value of the field.
+func (o Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// optionalField is implemented by every Optional type.
+type optionalField interface {
+	optionalState() (value any, present, null bool)
+	optionalType() reflect.Type
+}
+
+// absentOptional and nullOptional are the types of the nil values absent and null Optional fields are validated as,
+// telling their field errors apart from the ones of other nil fields.
+type (
+	absentOptional struct{}
+	nullOptional   struct{}
+)
+
+var (
+	optionalFieldType  = reflect.TypeFor[optionalField]()
+	absentOptionalType = reflect.TypeFor[*absentOptional]()
+	nullOptionalType   = reflect.TypeFor[*nullOptional]()
+)
+
+// ProvidedFields returns the JSON paths of the Optional fields present in the struct s, or a pointer to it,
+// including the ones explicitly null, e.g. to build the columns of a partial update. Nested structs are followed,
+// and the paths of their fields are joined with dots (e.g. address.street).
+func ProvidedFields(s any) []string {
+	var fields []string
+	collectProvidedFields(reflect.ValueOf(s), "", &fields)
+	return fields
+}
+
+// collectProvidedFields appends the paths of the present Optional fields of the struct value to fields.
+func collectProvidedFields(v reflect.Value, path string, fields *[]string) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() && !field.Anonymous || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		fieldPath := path
+		if name := fieldName(field); name != "" {
+			fieldPath = joinJSONPath(path, name)
+		}
+
+		value := v.Field(i)
+		if !value.CanInterface() {
+			continue
+		}
+		if optional, ok := value.Interface().(optionalField); ok {
+			inner, present, null := optional.optionalState()
+			if present {
+				*fields = append(*fields, fieldPath)
+				if !null {
+					collectProvidedFields(reflect.ValueOf(inner), fieldPath, fields)
+				}
+			}
+			continue
+		}
+		collectProvidedFields(value, fieldPath, fields)
+	}
+}
+
+// RegisterOptional registers the validation of the Optional[T] fields, which are validated as plain structs otherwise.
+// As the other registrations of the validator, it must happen before validations, e.g. at startup. NewValidator
+// registers the Optional types of string, bool, int, int64, float64 and time.Time.
+func RegisterOptional[T any](v *Validate) {
+	v.RegisterCustomTypeFunc(optionalValue, Optional[T]{})
+	v.optionalTypes[reflect.TypeFor[Optional[T]]()] = struct{}{}
+}
+
+// registerBuiltinOptionals registers the validation of the Optional types of the basic types.
+func (v *Validate) registerBuiltinOptionals() {
+	RegisterOptional[string](v)
+	RegisterOptional[bool](v)
+	RegisterOptional[int](v)
+	RegisterOptional[int64](v)
+	RegisterOptional[float64](v)
+	RegisterOptional[time.Time](v)
+}
+
+// checkOptionalTypes returns an error naming the Optional types found in the type t that are not registered,
+// checking each type once.
+func (v *Validate) checkOptionalTypes(t reflect.Type) error {
+	if checked, ok := v.checkedTypes.Load(t); ok {
+		err, _ := checked.(error)
+		return err
+	}
+
+	var errs []error
+	visitTypes(t, map[reflect.Type]bool{}, func(optional reflect.Type) {
+		if _, ok := v.optionalTypes[optional]; !ok {
+			errs = append(errs, fmt.Errorf("kit: %v is not registered, register it with RegisterOptional", optional))
+		}
+	})
+
+	err := errors.Join(errs...)
+	v.checkedTypes.Store(t, err)
+	return err
+}
+
+// visitTypes calls fn for each Optional type reachable from the type t through struct fields, pointers,
+// slices, arrays and maps.
+func visitTypes(t reflect.Type, seen map[reflect.Type]bool, fn func(reflect.Type)) {
+	t = indirectType(t)
+	if t == nil || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	if t.Implements(optionalFieldType) {
+		fn(t)
+		visitTypes(unwrapOptional(t), seen, fn)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			visitTypes(t.Field(i).Type, seen, fn)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		visitTypes(t.Elem(), seen, fn)
+	}
+}
+
+// optionalValue returns the value an Optional field is validated as: its value when provided, or a nil pointer
+// of the absentOptional or nullOptional types otherwise.
+func optionalValue(field reflect.Value) any {
+	value, present, null := field.Interface().(optionalField).optionalState()
+	switch {
+	case !present:
+		return (*absentOptional)(nil)
+	case null:
+		return (*nullOptional)(nil)
+	}
+	return value
+}
+
+// presentFieldErrors removes the errors of absent Optional fields, and of null Optional fields other than required.
+func presentFieldErrors(errs validator.ValidationErrors) validator.ValidationErrors {
+	kept := make(validator.ValidationErrors, 0, len(errs))
+	for _, fe := range errs {
+		switch fe.Type() {
+		case absentOptionalType:
+			continue
+		case nullOptionalType:
+			if fe.ActualTag() != "required" {
+				continue
+			}
+		}
+		kept = append(kept, fe)
+	}
+	return kept
+}
+
+// unwrapOptional returns the type of the value of an Optional type, or t itself for other types.
+func unwrapOptional(t reflect.Type) reflect.Type {
+	if t != nil && t.Implements(optionalFieldType) {
+		return reflect.Zero(t).Interface().(optionalField).optionalType()
+	}
+	return t
+}
+
+// valueType dereferences pointers and Optional types, returning the type of the value held by a field of type t.
+func valueType(t reflect.Type) reflect.Type {
+	return indirectType(unwrapOptional(indirectType(t)))
+}
@@ -0,0 +1,221 @@
+package kit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type patchAddress struct {
+	Street kit.Optional[string] `json:"street" validate:"required,max=10" custom:"Rua"`
+	City   string               `json:"city" validate:"required"`
+}
+
+type patchBeneficiary struct {
+	Name    kit.Optional[string]       `json:"name" validate:"required,min=3" normalize:"trim"`
+	Email   kit.Optional[string]       `json:"email" validate:"email"`
+	Age     kit.Optional[int]          `json:"age" validate:"omitempty,gte=0"`
+	Address kit.Optional[patchAddress] `json:"address"`
+	Phones  kit.Optional[[]string]     `json:"phones" validate:"max=2,dive,br_phone"`
+}
+
+// newPatchValidator returns a validator with the Optional types of patchBeneficiary registered.
+func newPatchValidator() *kit.Validate {
+	v := kit.NewValidator()
+	kit.RegisterOptional[patchAddress](v)
+	kit.RegisterOptional[[]string](v)
+	return v
+}
+
+func TestOptionalJSON(t *testing.T) {
+	var out patchBeneficiary
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Maria","email":null,"address":{"street":"Rua A"}}`), &out))
+
+	assert.Equal(t, kit.Some("Maria"), out.Name)
+	assert.Equal(t, kit.Null[string](), out.Email)
+	assert.Equal(t, kit.Optional[int]{}, out.Age)
+	assert.Equal(t, kit.Some(patchAddress{Street: kit.Some("Rua A")}), out.Address)
+
+	assert.Equal(t, "Maria", *out.Name.Ptr())
+	assert.Nil(t, out.Email.Ptr())
+	assert.Nil(t, out.Age.Ptr())
+	assert.False(t, out.Name.IsZero())
+	assert.True(t, out.Age.IsZero())
+
+	encoded, err := json.Marshal(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Maria","email":null,"age":null,"address":{"street":"Rua A","city":""},"phones":null}`,
+		string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"age":"ten"}`), &out))
+}
+
+func TestOptionalValidation(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              patchBeneficiary
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:  "Absent fields are not validated",
+			input: patchBeneficiary{},
+		},
+		{
+			name: "Present valid fields",
+			input: patchBeneficiary{
+				Name:    kit.Some("Maria"),
+				Email:   kit.Some("maria@example.com"),
+				Age:     kit.Some(0),
+				Address: kit.Some(patchAddress{City: "Recife"}),
+				Phones:  kit.Some([]string{"(11) 98765-4321"}),
+			},
+		},
+		{
+			name: "Null fields only fail required",
+			input: patchBeneficiary{
+				Name:    kit.Null[string](),
+				Email:   kit.Null[string](),
+				Address: kit.Null[patchAddress](),
+				Phones:  kit.Null[[]string](),
+			},
+			expectedViolations: []kit.Violation{
+				{Field: "name", Tag: "required", Message: "name é um campo obrigatório"},
+			},
+		},
+		{
+			name: "Present invalid fields",
+			input: patchBeneficiary{
+				Name:    kit.Some("Jo"),
+				Email:   kit.Some("invalid"),
+				Address: kit.Some(patchAddress{Street: kit.Null[string]()}),
+				Phones:  kit.Some([]string{"123"}),
+			},
+			expectedViolations: []kit.Violation{
+				{Field: "name", Tag: "min", Param: "3", Value: "Jo",
+					Message: "name deve ter pelo menos 3 caracteres"},
+				{Field: "email", Tag: "email", Value: "invalid",
+					Message: "email deve ser um endereço de e-mail válido"},
+				{Field: "address.street", Tag: "required",
+					Message: "address › Rua é um campo obrigatório"},
+				{Field: "address.city", Tag: "required", Value: "",
+					Message: "address.city é um campo obrigatório"},
+				{Field: "phones[0]", Tag: "br_phone", Value: "123",
+					Message: "phones[0] deve ser um telefone válido"},
+			},
+		},
+	}
+
+	v := newPatchValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.StructTranslated(tt.input)
+			if tt.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErrors *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErrors)
+			assert.Equal(t, tt.expectedViolations, validationErrors.Violations())
+		})
+	}
+}
+
+func TestProvidedFields(t *testing.T) {
+	type Patch struct {
+		ID       string `params:"id"`
+		Contract struct {
+			Plan kit.Optional[string] `json:"plan"`
+		} `json:"contract"`
+		Beneficiary *patchBeneficiary `json:"beneficiary"`
+		Skipped     *patchBeneficiary `json:"skipped"`
+	}
+
+	patch := Patch{Beneficiary: &patchBeneficiary{
+		Name:    kit.Some("Maria"),
+		Email:   kit.Null[string](),
+		Address: kit.Some(patchAddress{Street: kit.Some("Rua A")}),
+	}}
+	patch.Contract.Plan = kit.Some("gold")
+
+	assert.Equal(t, []string{
+		"contract.plan",
+		"beneficiary.name",
+		"beneficiary.email",
+		"beneficiary.address",
+		"beneficiary.address.street",
+	}, kit.ProvidedFields(&patch))
+	assert.Nil(t, kit.ProvidedFields(patchBeneficiary{}))
+}
+
+func TestParseRequestBodyOptional(t *testing.T) {
+	v := newPatchValidator()
+
+	tests := []struct {
+		name           string
+		body           string
+		parse          func(out any, c *fiber.Ctx, v kit.Validator) error
+		expectedFields []string
+		expectedSlug   string
+	}{
+		{
+			name:           "Partial update",
+			body:           `{"name":"  Maria  ","age":null}`,
+			parse:          kit.ParseRequestBody,
+			expectedFields: []string{"name", "age"},
+		},
+		{
+			name:         "Required field sent as null",
+			body:         `{"name":null}`,
+			parse:        kit.ParseRequestBody,
+			expectedSlug: "request-validation",
+		},
+		{
+			name: "Strict decoding checks the value type",
+			body: `{"age":"ten"}`,
+			parse: func(out any, c *fiber.Ctx, v kit.Validator) error {
+				return kit.ParseRequestBodyWithConfig(out, c, v, kit.BodyConfig{DisallowUnknownFields: true})
+			},
+			expectedSlug: "bad-input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out patchBeneficiary
+			err := parseRoute(t, fiber.MethodPatch, "/contracts/1", tt.body, nil, func(c *fiber.Ctx) error {
+				return tt.parse(&out, c, v)
+			})
+
+			if tt.expectedSlug == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedFields, kit.ProvidedFields(out))
+				assert.Equal(t, "Maria", out.Name.Value)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug)
+		})
+	}
+}
+
+func TestRegisterOptional(t *testing.T) {
+	v := kit.NewValidator()
+
+	err := v.StructTranslated(patchBeneficiary{})
+	require.Error(t, err)
+	assert.Equal(t, "kit: kit.Optional[github.com/arvo-health/kit_test.patchAddress] is not registered, register it with RegisterOptional\n"+
+		"kit: kit.Optional[[]string] is not registered, register it with RegisterOptional", err.Error())
+
+	kit.RegisterOptional[patchAddress](v)
+	kit.RegisterOptional[[]string](v)
+	assert.Error(t, v.StructTranslated(patchBeneficiary{}), "the check of a type is cached")
+	assert.NoError(t, v.StructTranslated(&patchBeneficiary{}))
+}
@@ -0,0 +1,229 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines the PDF page counter of the file_max_pages tag, which reads the page count of the root of the
+// page tree, following incremental updates and compressed object streams.
+
+package kit
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// pdfReadLimit is the maximum number of bytes read from a PDF, and decompressed from all of its object streams
+// together, to count its pages. Larger PDFs are reported as unreadable.
+const pdfReadLimit = 64 << 20
+
+var (
+	pdfObjectRegex  = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfRootRegex    = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	pdfPagesRegex   = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	pdfCountRegex   = regexp.MustCompile(`/Count\s+(\d+)\b`)
+	pdfLengthRegex  = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfNRegex       = regexp.MustCompile(`/N\s+(\d+)`)
+	pdfFirstRegex   = regexp.MustCompile(`/First\s+(\d+)`)
+	pdfObjStmRegex  = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	pdfCatalogRegex = regexp.MustCompile(`/Type\s*/Catalog\b`)
+	pdfFilterRegex  = regexp.MustCompile(`/Filter\s*(/\w+)`)
+)
+
+var (
+	// errUnreadablePDF is returned when the page count of a PDF cannot be read.
+	errUnreadablePDF = errors.New("unreadable PDF")
+	// errPDFReadLimit is returned when a PDF, or the data of its object streams, exceeds pdfReadLimit.
+	errPDFReadLimit = fmt.Errorf("%w: larger than %d bytes", errUnreadablePDF, pdfReadLimit)
+)
+
+// readPDFPages reads the page count of the PDF from the reader, reading at most pdfReadLimit bytes.
+func readPDFPages(r io.Reader) (int, error) {
+	content, err := io.ReadAll(io.LimitReader(r, pdfReadLimit+1))
+	if err != nil {
+		return 0, err
+	}
+	if len(content) > pdfReadLimit {
+		return 0, errPDFReadLimit
+	}
+	return pdfPages(content)
+}
+
+// pdfPages returns the /Count of the root of the page tree of the PDF, the one of the catalog named by the
+// last trailer. Objects redefined by incremental updates take the definition appended last.
+func pdfPages(content []byte) (int, error) {
+	objects, err := pdfObjects(content)
+	if err != nil {
+		return 0, err
+	}
+
+	var catalog []byte
+	if roots := pdfRootRegex.FindAllSubmatch(content, -1); len(roots) > 0 {
+		catalog = objects[pdfAtoi(roots[len(roots)-1][1])]
+	} else {
+		for _, object := range objects { // files without trailer, relying on the catalog alone
+			if pdfCatalogRegex.Match(object) {
+				catalog = object
+			}
+		}
+	}
+
+	m := pdfPagesRegex.FindSubmatch(catalog)
+	if m == nil {
+		return 0, fmt.Errorf("%w: page tree not found", errUnreadablePDF)
+	}
+	m = pdfCountRegex.FindSubmatch(objects[pdfAtoi(m[1])])
+	if m == nil {
+		return 0, fmt.Errorf("%w: page count not found", errUnreadablePDF)
+	}
+	return pdfAtoi(m[1]), nil
+}
+
+// pdfObjects returns the objects of the PDF by number, including the catalogs and page tree nodes of the compressed
+// object streams. Objects defined more than once keep their last definition. The object streams share a budget of
+// pdfReadLimit decompressed bytes, and exceeding it returns errPDFReadLimit.
+func pdfObjects(content []byte) (map[int][]byte, error) {
+	objects := map[int][]byte{}
+	budget := pdfReadLimit
+
+	for pos := 0; pos < len(content); {
+		loc := pdfObjectRegex.FindSubmatchIndex(content[pos:])
+		if loc == nil {
+			break
+		}
+		number := pdfAtoi(content[pos+loc[2] : pos+loc[3]])
+		start := pos + loc[1]
+
+		object, stream, end := pdfObject(content, start)
+		objects[number] = object
+		if stream != nil && pdfObjStmRegex.Match(object) {
+			streamed, err := pdfStreamObjects(object, stream, &budget)
+			if errors.Is(err, errPDFReadLimit) {
+				return nil, err
+			}
+			for n, streamedObject := range streamed {
+				objects[n] = streamedObject
+			}
+		}
+		pos = end
+	}
+	return objects, nil
+}
+
+// pdfObject returns the dictionary of the object starting at start, its stream data if any,
+// and the offset at which it ends.
+func pdfObject(content []byte, start int) ([]byte, []byte, int) {
+	end := bytes.Index(content[start:], []byte("endobj"))
+	if end < 0 {
+		end = len(content) - start
+	}
+	object := content[start : start+end]
+
+	keyword := bytes.Index(object, []byte("stream"))
+	if keyword < 0 {
+		return object, nil, start + end
+	}
+
+	dataStart := start + keyword + len("stream")
+	if bytes.HasPrefix(content[dataStart:], []byte("\r\n")) {
+		dataStart += 2
+	} else if bytes.HasPrefix(content[dataStart:], []byte("\n")) {
+		dataStart++
+	}
+
+	dataEnd := -1
+	if m := pdfLengthRegex.FindSubmatch(object[:keyword]); m != nil && len(m[2]) == 0 {
+		if length := pdfAtoi(m[1]); length >= 0 && dataStart+length <= len(content) {
+			dataEnd = dataStart + length // binary data may contain the endstream and endobj keywords
+		}
+	}
+	if dataEnd < 0 {
+		if i := bytes.Index(content[dataStart:], []byte("endstream")); i >= 0 {
+			dataEnd = dataStart + i
+		} else {
+			dataEnd = dataStart
+		}
+	}
+
+	end = bytes.Index(content[dataEnd:], []byte("endobj"))
+	if end < 0 {
+		end = len(content) - dataEnd
+	}
+	return object[:keyword], content[dataStart:dataEnd], dataEnd + end
+}
+
+// pdfStreamObjects returns the objects of a compressed object stream by number, taking the size of its decompressed
+// data from the budget. Only the catalogs and page tree nodes, the objects read to count the pages, are kept, as copies
+// releasing the data; the other objects are returned as nil, since they still replace earlier definitions.
+func pdfStreamObjects(dict, stream []byte, budget *int) (map[int][]byte, error) {
+	data, err := pdfStreamData(dict, stream, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	n, first := pdfNRegex.FindSubmatch(dict), pdfFirstRegex.FindSubmatch(dict)
+	if n == nil || first == nil || pdfAtoi(first[1]) < 0 || pdfAtoi(first[1]) > len(data) {
+		return nil, fmt.Errorf("%w: malformed object stream", errUnreadablePDF)
+	}
+
+	header := bytes.Fields(data[:pdfAtoi(first[1])])
+	count := min(pdfAtoi(n[1]), len(header)/2)
+	if count < 0 {
+		return nil, fmt.Errorf("%w: malformed object stream", errUnreadablePDF)
+	}
+	offsets := make([]int, count+1)
+	for i := range count {
+		offsets[i] = pdfAtoi(first[1]) + pdfAtoi(header[2*i+1])
+	}
+	offsets[count] = len(data)
+
+	objects := make(map[int][]byte, count)
+	for i := range count {
+		if 0 <= offsets[i] && offsets[i] <= offsets[i+1] && offsets[i+1] <= len(data) {
+			var object []byte
+			if o := data[offsets[i]:offsets[i+1]]; pdfCatalogRegex.Match(o) || pdfCountRegex.Match(o) {
+				object = bytes.Clone(o)
+			}
+			objects[pdfAtoi(header[2*i])] = object
+		}
+	}
+	return objects, nil
+}
+
+// pdfStreamData decodes the data of a stream, which may be compressed with FlateDecode, taking the size of the
+// decompressed data from the budget and returning errPDFReadLimit when it exceeds the budget left.
+func pdfStreamData(dict, stream []byte, budget *int) ([]byte, error) {
+	filter := pdfFilterRegex.FindSubmatch(dict)
+	if filter == nil {
+		return stream, nil
+	}
+	if string(filter[1]) != "/FlateDecode" {
+		return nil, fmt.Errorf("%w: unsupported filter %s", errUnreadablePDF, filter[1])
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(*budget)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > *budget {
+		return nil, errPDFReadLimit
+	}
+	*budget -= len(data)
+	return data, nil
+}
+
+// pdfAtoi parses the digits matched by the PDF regular expressions, returning -1 for numbers out of range.
+func pdfAtoi(b []byte) int {
+	n, err := strconv.Atoi(string(b))
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+// Package kit provides utilities for structured error handling and API response formatting.
+// This file defines the RFC 9457 problem details representation of an HTTPError.
+
+package kit
+
+import (
+	"net/http"
+)
+
+// MIMEApplicationProblemJSON is the media type of RFC 9457 problem details documents.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// ProblemDetails represents an RFC 9457 problem details document.
+// Besides the standard members, it carries the error slug, the request ID and
+// the validation details as extension members.
+type ProblemDetails struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Details   []Violation    `json:"details,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Debug     *ErrorDebug    `json:"debug,omitempty"`
+}
+
+// NewProblemDetails converts the HTTPError into a problem details document.
+// The type URI is built by appending the slug to typeBaseURI and the instance is the given request path.
+// The title falls back to the slug when the status has no standard reason phrase.
+func NewProblemDetails(e *HTTPError, typeBaseURI, instance string) *ProblemDetails {
+	title := http.StatusText(e.Status)
+	if title == "" {
+		title = e.Slug
+	}
+
+	return &ProblemDetails{
+		Type:     typeBaseURI + e.Slug,
+		Title:    title,
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Slug,
+		Details:  e.Details,
+		Metadata: e.Metadata,
+	}
+}
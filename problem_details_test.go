@@ -0,0 +1,49 @@
+package kit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProblemDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *kit.HTTPError
+		expected *kit.ProblemDetails
+	}{
+		{
+			name: "Standard status uses the reason phrase as title",
+			err:  kit.HTTPConflictError("user-exists", errors.New("user already exists")),
+			expected: &kit.ProblemDetails{
+				Type:     "urn:problem-type:user-exists",
+				Title:    "Conflict",
+				Status:   409,
+				Detail:   "user already exists",
+				Instance: "/users",
+				Code:     "user-exists",
+			},
+		},
+		{
+			name: "Non-standard status falls back to the slug as title",
+			err:  kit.NewHTTPError(499, "client-closed-request", errors.New("client closed request")),
+			expected: &kit.ProblemDetails{
+				Type:     "urn:problem-type:client-closed-request",
+				Title:    "client-closed-request",
+				Status:   499,
+				Detail:   "client closed request",
+				Instance: "/users",
+				Code:     "client-closed-request",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := kit.NewProblemDetails(tt.err, kit.DefaultProblemTypeBaseURI, "/users")
+			assert.Equal(t, tt.expected, problem)
+		})
+	}
+}
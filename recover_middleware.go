@@ -0,0 +1,70 @@
+// Package kit provides reusable middleware for Fiber applications.
+// This file defines a middleware that recovers from panics and converts them into structured errors.
+
+package kit
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecoverMiddleware is a Fiber middleware that recovers from panics in the next handlers and converts them
+// into an HTTPInternalServerError carrying the panic stack trace. The panic value and stack are logged with
+// the request-scoped logger stored at CtxKeyLogger, and the error is returned up the chain so that
+// LoggerMiddleware still emits its request record with status 500 and ErrorHandler renders the response.
+// It must be registered after LoggerMiddleware.
+func RecoverMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(c, r)
+			}
+		}()
+
+		return c.Next()
+	}
+}
+
+// recoveredError converts the recovered panic value into an HTTPError and logs it.
+func recoveredError(c *fiber.Ctx, r any) *HTTPError {
+	var panicErr error
+	if e, ok := r.(error); ok {
+		panicErr = fmt.Errorf("panic: %w", e)
+	} else {
+		panicErr = fmt.Errorf("panic: %v", r)
+	}
+
+	e := HTTPInternalServerError(panicErr)
+	e.stack = panicCallers()
+
+	log, ok := c.Locals(CtxKeyLogger).(*slog.Logger)
+	if !ok {
+		log = slog.Default()
+	}
+
+	log.LogAttrs(c.UserContext(), slog.LevelError, "panic recovered: "+fmt.Sprint(r),
+		slog.String("path", c.Path()),
+		slog.Any("panic", r),
+		slog.Any("stack", e.StackTrace()),
+	)
+
+	return e
+}
+
+// panicCallers captures the stack trace of a panic from within a deferred function,
+// starting at the frame that panicked.
+func panicCallers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	pcs = pcs[:n]
+
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			return pcs[i+1:]
+		}
+	}
+	return pcs
+}
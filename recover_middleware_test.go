@@ -0,0 +1,115 @@
+package kit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		panicValue     any
+		expectedChain  string
+		expectedRecord string
+	}{
+		{
+			name:           "Panic with a string value",
+			panicValue:     "something went wrong",
+			expectedChain:  "panic: something went wrong",
+			expectedRecord: "panic recovered: something went wrong",
+		},
+		{
+			name:           "Panic with an error value",
+			panicValue:     errors.New("nil map"),
+			expectedChain:  "panic: nil map",
+			expectedRecord: "panic recovered: nil map",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, handler := kit.NewTestLogger()
+
+			app := fiber.New(fiber.Config{
+				ErrorHandler: kit.ErrorHandlerWithConfig(logger, kit.ErrorHandlerConfig{Exposure: kit.ExposureDebug}),
+			})
+			app.Use(kit.LoggerMiddleware(logger))
+			app.Use(kit.RecoverMiddleware())
+			app.Get("/panic", func(c *fiber.Ctx) error {
+				panic(tt.panicValue)
+			}).Name("Panic")
+
+			req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+			req.Header.Set(kit.RequestIDHeaderKey, "request-id")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+			var respBody struct {
+				Error struct {
+					Code  string          `json:"code"`
+					Debug *kit.ErrorDebug `json:"debug"`
+				} `json:"error"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			assert.Equal(t, "unknown-error", respBody.Error.Code)
+			require.NotNil(t, respBody.Error.Debug)
+			assert.Equal(t, tt.expectedChain, respBody.Error.Debug.Chain[0])
+			require.NotEmpty(t, respBody.Error.Debug.Stack)
+			assert.Contains(t, respBody.Error.Debug.Stack[0], "kit_test.TestRecoverMiddleware", "Stack trace should start at the panicking handler")
+
+			records := handler.CapturedRecords()
+			require.Len(t, records, 2, "Expected the panic record and the request record")
+
+			assert.Equal(t, slog.LevelError, records[0].Level)
+			assert.Equal(t, tt.expectedRecord, records[0].Message)
+			assert.Equal(t, "request-id", recordAttr(records[0], "request_id").String(), "The panic should be logged with the request-scoped logger")
+			assert.NotEmpty(t, recordAttr(records[0], "stack").Any())
+
+			assert.Equal(t, slog.LevelError, records[1].Level)
+			assert.Equal(t, "Panic: request failed: "+tt.expectedChain, records[1].Message)
+			status := recordAttr(records[1], "response").Group()
+			assert.Contains(t, status, slog.Int("status", http.StatusInternalServerError))
+		})
+	}
+}
+
+func TestRecoverMiddlewareWithoutPanic(t *testing.T) {
+	app := fiber.New()
+	app.Use(kit.RecoverMiddleware())
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // The error is intentionally ignored as it is non-critical for this operation
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// recordAttr returns the value of the top-level attribute with the given key in the log record.
+func recordAttr(record slog.Record, key string) slog.Value {
+	var value slog.Value
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == key {
+			value = attr.Value
+			return false
+		}
+		return true
+	})
+	return value
+}
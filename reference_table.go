@@ -0,0 +1,176 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines the reference tables of healthcare codes (CID-10, TUSS, CBO and ANS registry)
+// backing the healthcare validation tags. The tables are embedded in the binary and can be
+// refreshed at startup from local copies of the official files.
+
+package kit
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//go:embed reference/*.txt
+var referenceFiles embed.FS
+
+// Reference tables backing the cid10, tuss, cbo and ans validation tags of the validators created by NewValidator,
+// loaded with the snapshots embedded in the binary. LoadFile refreshes them with the complete official tables.
+var (
+	CID10Codes   = NewCID10Table()
+	TUSSCodes    = NewTUSSTable()
+	CBOCodes     = NewCBOTable()
+	ANSOperators = NewANSTable()
+)
+
+var (
+	cid10Regex = regexp.MustCompile(`^[A-Z]\d{2}(\.?\d)?$`)
+	tussRegex  = regexp.MustCompile(`^\d\.?\d{2}\.?\d{2}\.?\d{2}-?\d$`)
+	cboRegex   = regexp.MustCompile(`^\d{4}-?\d{2}$`)
+	ansRegex   = regexp.MustCompile(`^\d{2}\.?\d{3}-?\d$`)
+)
+
+func init() {
+	for _, table := range []*ReferenceTable{CID10Codes, TUSSCodes, CBOCodes, ANSOperators} {
+		f, err := referenceFiles.Open("reference/" + table.name + ".txt")
+		if err != nil {
+			panic(err)
+		}
+		if err := table.Load(f); err != nil {
+			panic(err)
+		}
+		_ = f.Close()
+	}
+}
+
+// NewCID10Table creates an empty table of CID-10 diagnosis codes, backing the cid10 tag once registered.
+func NewCID10Table() *ReferenceTable {
+	return NewReferenceTable("cid10", cid10Regex, normalizeCID10)
+}
+
+// NewTUSSTable creates an empty table of TUSS procedure codes, backing the tuss tag once registered.
+func NewTUSSTable() *ReferenceTable {
+	return NewReferenceTable("tuss", tussRegex, onlyDigits)
+}
+
+// NewCBOTable creates an empty table of CBO occupation codes, backing the cbo tag once registered.
+func NewCBOTable() *ReferenceTable {
+	return NewReferenceTable("cbo", cboRegex, onlyDigits)
+}
+
+// NewANSTable creates an empty table of ANS operator registry numbers, backing the ans tag once registered.
+func NewANSTable() *ReferenceTable {
+	return NewReferenceTable("ans", ansRegex, onlyDigits)
+}
+
+// ReferenceTable is a concurrency-safe set of codes, with their descriptions, accepted by a validation tag.
+// Codes are matched regardless of formatting, e.g. the CID-10 code "J45.0" matches "J450".
+type ReferenceTable struct {
+	name      string
+	format    *regexp.Regexp
+	normalize func(string) string
+
+	mu      sync.RWMutex
+	entries map[string]string
+}
+
+// NewReferenceTable creates an empty ReferenceTable whose codes must match the format,
+// and are compared after applying normalize.
+func NewReferenceTable(name string, format *regexp.Regexp, normalize func(string) string) *ReferenceTable {
+	return &ReferenceTable{
+		name:      name,
+		format:    format,
+		normalize: normalize,
+		entries:   map[string]string{},
+	}
+}
+
+// Name returns the name of the reference table.
+func (t *ReferenceTable) Name() string {
+	return t.name
+}
+
+// Valid reports whether the code has the format of the table and is one of its codes.
+func (t *ReferenceTable) Valid(code string) bool {
+	_, found := t.Lookup(code)
+	return found
+}
+
+// Lookup returns the description of the code, and whether the code has the format of the table and is one of its codes.
+func (t *ReferenceTable) Lookup(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !t.format.MatchString(code) {
+		return "", false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	description, found := t.entries[t.normalize(code)]
+	return description, found
+}
+
+// Len returns the number of codes in the table.
+func (t *ReferenceTable) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.entries)
+}
+
+// Load replaces the codes of the table with the ones read from r, one per line as "code;description".
+// The description is optional, and blank lines and lines starting with # are ignored.
+// The table is left unchanged if any code is malformed or if no code is read.
+func (t *ReferenceTable) Load(r io.Reader) error {
+	entries := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		code, description, _ := strings.Cut(text, ";")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !t.format.MatchString(code) {
+			return fmt.Errorf("reference table %s: line %d: malformed code %q", t.name, line, code)
+		}
+		entries[t.normalize(code)] = strings.TrimSpace(description)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reference table %s: %w", t.name, err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("reference table %s: %w", t.name, errors.New("no codes found"))
+	}
+
+	t.mu.Lock()
+	t.entries = entries
+	t.mu.Unlock()
+
+	return nil
+}
+
+// LoadFile replaces the codes of the table with the ones of the file at path, in the format accepted by Load.
+func (t *ReferenceTable) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reference table %s: %w", t.name, err)
+	}
+	defer f.Close()
+
+	return t.Load(f)
+}
+
+// normalizeCID10 removes the dot separating the CID-10 category from its subcategory.
+func normalizeCID10(code string) string {
+	return strings.ReplaceAll(code, ".", "")
+}
@@ -0,0 +1,127 @@
+package kit_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceTable_Lookup(t *testing.T) {
+	tables := referenceTables(t)
+	cid10Codes, tussCodes, cboCodes, ansOperators := tables[0], tables[1], tables[2], tables[3]
+
+	tests := []struct {
+		name                string
+		table               *kit.ReferenceTable
+		code                string
+		expectedFound       bool
+		expectedDescription string
+	}{
+		{name: "CID-10 category", table: cid10Codes, code: "I10", expectedFound: true, expectedDescription: "Hipertensão essencial (primária)"},
+		{name: "CID-10 subcategory with dot", table: cid10Codes, code: "J45.9", expectedFound: true, expectedDescription: "Asma não especificada"},
+		{name: "CID-10 subcategory without dot", table: cid10Codes, code: "J459", expectedFound: true, expectedDescription: "Asma não especificada"},
+		{name: "CID-10 lowercase", table: cid10Codes, code: "j45", expectedFound: true, expectedDescription: "Asma"},
+		{name: "CID-10 unknown code", table: cid10Codes, code: "J99.9", expectedFound: false},
+		{name: "CID-10 malformed code", table: cid10Codes, code: "J4", expectedFound: false},
+		{name: "TUSS digits only", table: tussCodes, code: "10101012", expectedFound: true, expectedDescription: "Consulta em consultório (no horário normal ou preestabelecido)"},
+		{name: "TUSS formatted", table: tussCodes, code: "1.01.01.01-2", expectedFound: true, expectedDescription: "Consulta em consultório (no horário normal ou preestabelecido)"},
+		{name: "TUSS unknown code", table: tussCodes, code: "10101013", expectedFound: false},
+		{name: "CBO formatted", table: cboCodes, code: "2251-25", expectedFound: true, expectedDescription: "Médico clínico"},
+		{name: "CBO digits only", table: cboCodes, code: "225125", expectedFound: true, expectedDescription: "Médico clínico"},
+		{name: "CBO malformed code", table: cboCodes, code: "22512", expectedFound: false},
+		{name: "ANS registry", table: ansOperators, code: "326305", expectedFound: true, expectedDescription: "Amil Assistência Médica Internacional"},
+		{name: "ANS registry formatted", table: ansOperators, code: "32.630-5", expectedFound: true, expectedDescription: "Amil Assistência Médica Internacional"},
+		{name: "ANS unknown registry", table: ansOperators, code: "999999", expectedFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description, found := tt.table.Lookup(tt.code)
+
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expectedDescription, description)
+			assert.Equal(t, tt.expectedFound, tt.table.Valid(tt.code))
+		})
+	}
+}
+
+func TestReferenceTable_Load(t *testing.T) {
+	newTable := func() *kit.ReferenceTable {
+		return kit.NewReferenceTable("codes", regexp.MustCompile(`^\d{3}$`), strings.TrimSpace)
+	}
+
+	t.Run("Replaces the codes", func(t *testing.T) {
+		table := newTable()
+		require.NoError(t, table.Load(strings.NewReader("# comment\n\n100;First\n200\n")))
+		require.NoError(t, table.Load(strings.NewReader("300;Third\n")))
+
+		assert.Equal(t, "codes", table.Name())
+		assert.Equal(t, 1, table.Len())
+		assert.False(t, table.Valid("100"))
+
+		description, found := table.Lookup("300")
+		assert.True(t, found)
+		assert.Equal(t, "Third", description)
+	})
+
+	t.Run("Code without description", func(t *testing.T) {
+		table := newTable()
+		require.NoError(t, table.Load(strings.NewReader("200\n")))
+
+		description, found := table.Lookup("200")
+		assert.True(t, found)
+		assert.Empty(t, description)
+	})
+
+	t.Run("Malformed code keeps the table unchanged", func(t *testing.T) {
+		table := newTable()
+		require.NoError(t, table.Load(strings.NewReader("100\n")))
+
+		err := table.Load(strings.NewReader("200\n20A;Malformed\n"))
+
+		assert.EqualError(t, err, `reference table codes: line 2: malformed code "20A"`)
+		assert.True(t, table.Valid("100"))
+		assert.False(t, table.Valid("200"))
+	})
+
+	t.Run("Empty file keeps the table unchanged", func(t *testing.T) {
+		table := newTable()
+		require.NoError(t, table.Load(strings.NewReader("100\n")))
+
+		err := table.Load(strings.NewReader("# no codes\n"))
+
+		assert.EqualError(t, err, "reference table codes: no codes found")
+		assert.Equal(t, 1, table.Len())
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		err := newTable().Load(failingReader{})
+
+		assert.ErrorContains(t, err, "reference table codes:")
+	})
+}
+
+func TestReferenceTable_LoadFile(t *testing.T) {
+	table := kit.NewReferenceTable("codes", regexp.MustCompile(`^\d{3}$`), strings.TrimSpace)
+
+	t.Run("Existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "codes.txt")
+		require.NoError(t, os.WriteFile(path, []byte("100;First\n200;Second\n"), 0o600))
+
+		require.NoError(t, table.LoadFile(path))
+		assert.Equal(t, 2, table.Len())
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		err := table.LoadFile(filepath.Join(t.TempDir(), "missing.txt"))
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Equal(t, 2, table.Len())
+	})
+}
@@ -7,12 +7,18 @@ import (
 )
 
 // MockLogHandler is a mocked log handler for capturing and testing log records.
+// Handlers derived through WithAttrs and WithGroup share the captured records with their parent.
 type MockLogHandler struct {
+	store  *mockLogStore
+	level  slog.Level
+	attrs  []slog.Attr
+	groups []string
+}
+
+// mockLogStore holds the records captured by a MockLogHandler and the handlers derived from it.
+type mockLogStore struct {
 	mu      sync.Mutex
 	records []slog.Record
-	level   slog.Level
-	attrs   []slog.Attr
-	groups  []string
 }
 
 // NewTestLogger creates a new test logger and its associated mock log handler for testing log outputs.
@@ -25,10 +31,10 @@ func NewTestLogger() (*slog.Logger, *MockLogHandler) {
 // NewMockLogHandlerWithLevel creates a new MockLogHandler with a specified minimum log level.
 func NewMockLogHandlerWithLevel(level slog.Level) *MockLogHandler {
 	return &MockLogHandler{
-		level:   level,
-		records: []slog.Record{},
-		attrs:   []slog.Attr{},
-		groups:  []string{},
+		store:  &mockLogStore{records: []slog.Record{}},
+		level:  level,
+		attrs:  []slog.Attr{},
+		groups: []string{},
 	}
 }
 
@@ -39,11 +45,11 @@ func NewMockLogHandler() *MockLogHandler {
 
 // Handle processes a log record by appending it to the handler's records and applying contextual attributes.
 func (h *MockLogHandler) Handle(ctx context.Context, r slog.Record) error {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
 
 	r.AddAttrs(h.attrs...)
-	h.records = append(h.records, r)
+	h.store.records = append(h.store.records, r)
 	return nil
 }
 
@@ -54,27 +60,21 @@ func (h *MockLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // CapturedRecords returns a copy of the captured log records stored in the handler.
 func (h *MockLogHandler) CapturedRecords() []slog.Record {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-	return h.records
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return append([]slog.Record(nil), h.store.records...)
 }
 
 // WithAttrs returns a new handler instance with the provided attributes appended to the existing attributes.
 func (h *MockLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	newHandler := *h //nolint:govet // Test purpose
-	newHandler.attrs = append(h.attrs, attrs...)
+	newHandler := *h
+	newHandler.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
 	return &newHandler
 }
 
 // WithGroup creates and returns a new handler with the provided group name appended to the existing group hierarchy.
 func (h *MockLogHandler) WithGroup(name string) slog.Handler {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	newHandler := *h //nolint:govet // Test purpose
-	newHandler.groups = append(h.groups, name)
+	newHandler := *h
+	newHandler.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
 	return &newHandler
 }
@@ -31,6 +31,12 @@ func TestAssertErrorIs(t *testing.T) {
 			expectedError:  customError,
 			expectMatching: true,
 		},
+		{
+			name:           "HTTPError Matching by slug",
+			givenError:     kit.HTTPNotFoundError("user-not-found", errors.New("user 10 not found")),
+			expectedError:  kit.HTTPNotFoundError("user-not-found", nil),
+			expectMatching: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,537 @@
+// Package kit provides utilities for handling HTTP request parsing and validation.
+// This file defines the messages of the ANS TISS standard (Troca de Informação em Saúde Suplementar) exchanged with
+// health insurance operators: the typed structs of the guide batches (consultation, SP/SADT and hospitalization),
+// their decoding and validation against the structure, mandatory fields and code tables of the standard,
+// and the verification of the hash of the messages.
+
+package kit
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TISSNamespace is the XML namespace of the TISS schemas.
+const TISSNamespace = "http://www.ans.gov.br/padroes/tiss/schemas"
+
+// TISSVersion is the version of the TISS standard of the messages rendered when the received one is unknown.
+const TISSVersion = "4.01.00"
+
+// Tags of the violations reported by DecodeTISS, besides the ones of the validation rules.
+const (
+	TagTISSSyntax    = "tiss_syntax"
+	TagTISSStructure = "tiss_structure"
+	TagTISSHash      = "tiss_hash"
+)
+
+// CodeTISSInvalidMessage is the error code of TISS messages rejected by DecodeTISS, whose violations are its details.
+var CodeTISSInvalidMessage = ErrorCode{
+	Slug:   "tiss-invalid-message",
+	Status: http.StatusUnprocessableEntity,
+	Messages: map[string]string{
+		LocalePtBR: "mensagem TISS inválida",
+		LocaleEn:   "invalid TISS message",
+		LocaleEs:   "mensaje TISS inválido",
+	},
+}
+
+func init() {
+	DefaultErrorCatalog.MustRegister(CodeTISSInvalidMessage)
+}
+
+// tissMessages are the message templates of the TISS violations per locale, which receive the param as {0}.
+var tissMessages = map[string]map[string]string{
+	TagTISSSyntax: {
+		LocalePtBR: "XML inválido na linha {0}",
+		LocaleEn:   "invalid XML at line {0}",
+		LocaleEs:   "XML inválido en la línea {0}",
+	},
+	TagTISSStructure: {
+		LocalePtBR: "o elemento raiz deve ser mensagemTISS do namespace {0}",
+		LocaleEn:   "the root element must be mensagemTISS of the {0} namespace",
+		LocaleEs:   "el elemento raíz debe ser mensagemTISS del namespace {0}",
+	},
+	TagTISSHash: {
+		LocalePtBR: "o hash não corresponde ao conteúdo da mensagem",
+		LocaleEn:   "the hash does not match the content of the message",
+		LocaleEs:   "el hash no corresponde al contenido del mensaje",
+	},
+}
+
+// TISSMessage is a TISS message (mensagemTISS). Messages sent by providers carry a batch of guides in
+// ProviderToOperator; the responses of operators carry its receipt in OperatorToProvider.
+type TISSMessage struct {
+	XMLName            xml.Name                `xml:"http://www.ans.gov.br/padroes/tiss/schemas mensagemTISS"`
+	Header             TISSHeader              `xml:"cabecalho" custom:"Cabeçalho"`
+	ProviderToOperator *TISSProviderToOperator `xml:"prestadorParaOperadora" validate:"at_least_one_of=OperatorToProvider,mutually_exclusive=OperatorToProvider" custom:"Prestador para operadora"`
+	OperatorToProvider *TISSOperatorToProvider `xml:"operadoraParaPrestador" custom:"Operadora para prestador"`
+	Epilogue           TISSEpilogue            `xml:"epilogo" custom:"Epílogo"`
+}
+
+// TISSHeader is the header of a TISS message (cabecalho).
+type TISSHeader struct {
+	Transaction TISSTransaction `xml:"identificacaoTransacao" custom:"Identificação da transação"`
+	Origin      TISSParty       `xml:"origem" custom:"Origem"`
+	Destination TISSParty       `xml:"destino" custom:"Destino"`
+	Version     string          `xml:"Padrao" validate:"required" custom:"Padrão"`
+}
+
+// TISSTransaction identifies the transaction of a TISS message (identificacaoTransacao).
+type TISSTransaction struct {
+	Type     string `xml:"tipoTransacao" validate:"required,oneof=ENVIO_LOTE_GUIAS PROTOCOLO_RECEBIMENTO" custom:"Tipo de transação"`
+	Sequence string `xml:"sequencialTransacao" validate:"required,numeric,max=12" custom:"Sequencial da transação"`
+	Date     string `xml:"dataRegistroTransacao" validate:"required,datetime=2006-01-02" custom:"Data de registro"`
+	Time     string `xml:"horaRegistroTransacao" validate:"required,datetime=15:04:05" custom:"Hora de registro"`
+}
+
+// TISSParty is the origin or the destination of a TISS message: a provider or an operator, by its ANS registry.
+type TISSParty struct {
+	Provider    *TISSProviderID `xml:"identificacaoPrestador" validate:"at_least_one_of=ANSRegistry,mutually_exclusive=ANSRegistry" custom:"Prestador"`
+	ANSRegistry string          `xml:"registroANS,omitempty" custom:"Registro ANS"`
+}
+
+// TISSProviderID identifies a provider in the header of a TISS message (identificacaoPrestador).
+type TISSProviderID struct {
+	OperatorCode string `xml:"codigoPrestadorNaOperadora,omitempty" validate:"at_least_one_of=CNPJ CPF,mutually_exclusive=CNPJ CPF,max=14" custom:"Código na operadora"`
+	CNPJ         string `xml:"CNPJ,omitempty" validate:"omitempty,cnpj,mutually_exclusive=CPF" custom:"CNPJ"`
+	CPF          string `xml:"CPF,omitempty" validate:"omitempty,cpf" custom:"CPF"`
+}
+
+// TISSEpilogue is the epilogue of a TISS message (epilogo), with the hash of its content.
+type TISSEpilogue struct {
+	Hash string `xml:"hash"`
+}
+
+// TISSProviderToOperator is the content of the messages sent by providers (prestadorParaOperadora).
+type TISSProviderToOperator struct {
+	Batch *TISSBatch `xml:"loteGuias" validate:"required" custom:"Lote de guias"`
+}
+
+// TISSBatch is a batch of guides of a single type (loteGuias), up to 100 guides.
+type TISSBatch struct {
+	Number string     `xml:"numeroLote" validate:"required,numeric,max=12" custom:"Número do lote"`
+	Guides TISSGuides `xml:"guiasTISS" custom:"Guias"`
+}
+
+// TISSGuides are the guides of a batch (guiasTISS).
+type TISSGuides struct {
+	Consultations    []TISSConsultationGuide    `xml:"guiaConsulta" validate:"at_least_one_of=SPSADT Hospitalizations,mutually_exclusive=SPSADT Hospitalizations,max=100,dive" custom:"Guia de consulta"`
+	SPSADT           []TISSSPSADTGuide          `xml:"guiaSP-SADT" validate:"mutually_exclusive=Hospitalizations,max=100,dive" custom:"Guia SP/SADT"`
+	Hospitalizations []TISSHospitalizationGuide `xml:"guiaResumoInternacao" validate:"max=100,dive" custom:"Guia de resumo de internação"`
+}
+
+// TISSGuideHeader is the header of a guide (cabecalhoGuia and cabecalhoConsulta).
+type TISSGuideHeader struct {
+	ANSRegistry    string `xml:"registroANS" validate:"required" custom:"Registro ANS"`
+	ProviderNumber string `xml:"numeroGuiaPrestador" validate:"required,max=20" custom:"Número da guia no prestador"`
+	MainGuide      string `xml:"guiaPrincipal,omitempty" validate:"max=20" custom:"Guia principal"`
+}
+
+// TISSAuthorization is the authorization of a guide by the operator (dadosAutorizacao).
+type TISSAuthorization struct {
+	OperatorNumber string `xml:"numeroGuiaOperadora,omitempty" validate:"max=20" custom:"Número da guia na operadora"`
+	Date           string `xml:"dataAutorizacao" validate:"required,datetime=2006-01-02" custom:"Data da autorização"`
+	Password       string `xml:"senha,omitempty" validate:"max=20" custom:"Senha"`
+	PasswordExpiry string `xml:"dataValidadeSenha,omitempty" validate:"omitempty,datetime=2006-01-02,after_or_equal=Date" custom:"Validade da senha"`
+}
+
+// TISSBeneficiary is the beneficiary of a guide (dadosBeneficiario).
+type TISSBeneficiary struct {
+	CardNumber string `xml:"numeroCarteira" validate:"required,max=20" custom:"Número da carteira"`
+	Newborn    string `xml:"atendimentoRN" validate:"required,oneof=S N" custom:"Atendimento ao recém-nato"`
+	Name       string `xml:"nomeBeneficiario,omitempty" validate:"max=70" custom:"Nome do beneficiário"`
+	CNS        string `xml:"numeroCNS,omitempty" validate:"omitempty,cns" custom:"CNS"`
+}
+
+// TISSContractor is a provider identified in a guide (contratadoSolicitante and contratadoExecutante).
+type TISSContractor struct {
+	OperatorCode string `xml:"codigoPrestadorNaOperadora,omitempty" validate:"at_least_one_of=CPF CNPJ,mutually_exclusive=CPF CNPJ,max=14" custom:"Código na operadora"`
+	CPF          string `xml:"cpfContratado,omitempty" validate:"omitempty,cpf,mutually_exclusive=CNPJ" custom:"CPF do contratado"`
+	CNPJ         string `xml:"cnpjContratado,omitempty" validate:"omitempty,cnpj" custom:"CNPJ do contratado"`
+	Name         string `xml:"nomeContratado,omitempty" validate:"max=70" custom:"Nome do contratado"`
+}
+
+// TISSProfessional is a health professional of a guide (profissionalSolicitante and profissionalExecutante),
+// with the council (table 26), the state (IBGE code, table 59) and the occupation (CBO, table 24).
+type TISSProfessional struct {
+	Name          string `xml:"nomeProfissional,omitempty" validate:"max=70" custom:"Nome do profissional"`
+	Council       string `xml:"conselhoProfissional" validate:"required,len=2,numeric" custom:"Conselho profissional"`
+	CouncilNumber string `xml:"numeroConselhoProfissional" validate:"required,max=15" custom:"Número no conselho"`
+	State         string `xml:"UF" validate:"required,len=2,numeric" custom:"UF"`
+	CBO           string `xml:"CBOS" validate:"required" custom:"CBO"`
+}
+
+// TISSProcedure is a procedure or item of a guide (procedimento), coded in the table of codigoTabela (table 87).
+// Codes of table 22 must be TUSS procedures.
+type TISSProcedure struct {
+	Table       string  `xml:"codigoTabela" validate:"required,len=2,numeric" custom:"Tabela"`
+	Code        string  `xml:"codigoProcedimento" validate:"required,max=10" custom:"Código do procedimento"`
+	Description string  `xml:"descricaoProcedimento,omitempty" validate:"max=150" custom:"Descrição do procedimento"`
+	Value       float64 `xml:"valorProcedimento,omitempty" validate:"gte=0" custom:"Valor do procedimento"`
+}
+
+// TISSExecutedProcedure is a procedure executed in a SP/SADT or hospitalization guide (procedimentoExecutado).
+type TISSExecutedProcedure struct {
+	Date       string        `xml:"dataExecucao" validate:"required,datetime=2006-01-02" custom:"Data de execução"`
+	StartTime  string        `xml:"horaInicial,omitempty" validate:"omitempty,datetime=15:04:05" custom:"Hora inicial"`
+	EndTime    string        `xml:"horaFinal,omitempty" validate:"omitempty,datetime=15:04:05" custom:"Hora final"`
+	Procedure  TISSProcedure `xml:"procedimento" custom:"Procedimento"`
+	Quantity   float64       `xml:"quantidadeExecutada" validate:"gt=0" custom:"Quantidade executada"`
+	UnitValue  float64       `xml:"valorUnitario" validate:"gte=0" custom:"Valor unitário"`
+	TotalValue float64       `xml:"valorTotal" validate:"gte=0" custom:"Valor total"`
+}
+
+// TISSTotals are the totals of a SP/SADT or hospitalization guide (valorTotal).
+type TISSTotals struct {
+	Procedures float64 `xml:"valorProcedimentos,omitempty" validate:"gte=0" custom:"Valor dos procedimentos"`
+	Daily      float64 `xml:"valorDiarias,omitempty" validate:"gte=0" custom:"Valor das diárias"`
+	Fees       float64 `xml:"valorTaxasAlugueis,omitempty" validate:"gte=0" custom:"Valor das taxas e aluguéis"`
+	Materials  float64 `xml:"valorMateriais,omitempty" validate:"gte=0" custom:"Valor dos materiais"`
+	Medicines  float64 `xml:"valorMedicamentos,omitempty" validate:"gte=0" custom:"Valor dos medicamentos"`
+	OPME       float64 `xml:"valorOPME,omitempty" validate:"gte=0" custom:"Valor das OPME"`
+	Gases      float64 `xml:"valorGasesMedicinais,omitempty" validate:"gte=0" custom:"Valor dos gases medicinais"`
+	Total      float64 `xml:"valorTotalGeral" validate:"gte=0" custom:"Valor total geral"`
+}
+
+// TISSConsultationGuide is a consultation guide (guiaConsulta).
+type TISSConsultationGuide struct {
+	Header         TISSGuideHeader          `xml:"cabecalhoConsulta" custom:"Cabeçalho"`
+	OperatorNumber string                   `xml:"numeroGuiaOperadora,omitempty" validate:"max=20" custom:"Número da guia na operadora"`
+	Beneficiary    TISSBeneficiary          `xml:"dadosBeneficiario" custom:"Beneficiário"`
+	Contractor     TISSContractor           `xml:"contratadoExecutante" custom:"Contratado executante"`
+	CNES           string                   `xml:"CNES" validate:"required,max=7" custom:"CNES"`
+	Professional   TISSProfessional         `xml:"profissionalExecutante" custom:"Profissional executante"`
+	Accident       string                   `xml:"indicacaoAcidente" validate:"required,oneof=0 1 2 9" custom:"Indicação de acidente"`
+	Care           TISSConsultationCareData `xml:"dadosAtendimento" custom:"Atendimento"`
+	Notes          string                   `xml:"observacao,omitempty" validate:"max=500" custom:"Observação"`
+}
+
+// TISSConsultationCareData is the care of a consultation guide (dadosAtendimento).
+type TISSConsultationCareData struct {
+	Date             string        `xml:"dataAtendimento" validate:"required,datetime=2006-01-02" custom:"Data do atendimento"`
+	ConsultationType string        `xml:"tipoConsulta" validate:"required,oneof=1 2 3 4" custom:"Tipo de consulta"`
+	Procedure        TISSProcedure `xml:"procedimento" custom:"Procedimento"`
+}
+
+// TISSSPSADTGuide is a guide of professional services and diagnostic and therapeutic support (guiaSP-SADT).
+type TISSSPSADTGuide struct {
+	Header        TISSGuideHeader         `xml:"cabecalhoGuia" custom:"Cabeçalho"`
+	Authorization *TISSAuthorization      `xml:"dadosAutorizacao" custom:"Autorização"`
+	Beneficiary   TISSBeneficiary         `xml:"dadosBeneficiario" custom:"Beneficiário"`
+	Requester     TISSRequester           `xml:"dadosSolicitante" custom:"Solicitante"`
+	Request       TISSRequest             `xml:"dadosSolicitacao" custom:"Solicitação"`
+	Executor      TISSExecutor            `xml:"dadosExecutante" custom:"Executante"`
+	Care          TISSSPSADTCareData      `xml:"dadosAtendimento" custom:"Atendimento"`
+	Procedures    []TISSExecutedProcedure `xml:"procedimentosExecutados>procedimentoExecutado" validate:"dive" custom:"Procedimento executado"`
+	Totals        TISSTotals              `xml:"valorTotal" custom:"Valor total"`
+}
+
+// TISSRequester is the requester of a SP/SADT guide (dadosSolicitante).
+type TISSRequester struct {
+	Contractor   TISSContractor   `xml:"contratadoSolicitante" custom:"Contratado solicitante"`
+	Professional TISSProfessional `xml:"profissionalSolicitante" custom:"Profissional solicitante"`
+}
+
+// TISSRequest is the request of a SP/SADT guide (dadosSolicitacao).
+type TISSRequest struct {
+	Date               string `xml:"dataSolicitacao,omitempty" validate:"omitempty,datetime=2006-01-02" custom:"Data da solicitação"`
+	Character          string `xml:"caraterAtendimento" validate:"required,oneof=1 2" custom:"Caráter do atendimento"`
+	ClinicalIndication string `xml:"indicacaoClinica,omitempty" validate:"max=500" custom:"Indicação clínica"`
+}
+
+// TISSExecutor is the provider executing a SP/SADT or hospitalization guide (dadosExecutante).
+type TISSExecutor struct {
+	Contractor TISSContractor `xml:"contratadoExecutante" custom:"Contratado executante"`
+	CNES       string         `xml:"CNES" validate:"required,max=7" custom:"CNES"`
+}
+
+// TISSSPSADTCareData is the care of a SP/SADT guide (dadosAtendimento), coded by the tables 50, 36, 52, 39 and 76.
+type TISSSPSADTCareData struct {
+	Type             string `xml:"tipoAtendimento" validate:"required,len=2,numeric" custom:"Tipo de atendimento"`
+	Accident         string `xml:"indicacaoAcidente" validate:"required,oneof=0 1 2 9" custom:"Indicação de acidente"`
+	ConsultationType string `xml:"tipoConsulta,omitempty" validate:"omitempty,oneof=1 2 3 4" custom:"Tipo de consulta"`
+	ClosingReason    string `xml:"motivoEncerramento,omitempty" validate:"omitempty,len=2,numeric" custom:"Motivo de encerramento"`
+	Regime           string `xml:"regimeAtendimento" validate:"required,len=2,numeric" custom:"Regime de atendimento"`
+}
+
+// TISSHospitalizationGuide is a hospitalization summary guide (guiaResumoInternacao).
+type TISSHospitalizationGuide struct {
+	Header          TISSGuideHeader         `xml:"cabecalhoGuia" custom:"Cabeçalho"`
+	RequestNumber   string                  `xml:"numeroGuiaSolicitacaoInternacao" validate:"required,max=20" custom:"Número da guia de solicitação"`
+	Authorization   TISSAuthorization       `xml:"dadosAutorizacao" custom:"Autorização"`
+	Beneficiary     TISSBeneficiary         `xml:"dadosBeneficiario" custom:"Beneficiário"`
+	Executor        TISSExecutor            `xml:"dadosExecutante" custom:"Executante"`
+	Hospitalization TISSHospitalization     `xml:"dadosInternacao" custom:"Internação"`
+	Discharge       TISSDischarge           `xml:"dadosSaidaInternacao" custom:"Saída"`
+	Procedures      []TISSExecutedProcedure `xml:"procedimentosExecutados>procedimentoExecutado" validate:"dive" custom:"Procedimento executado"`
+	Totals          TISSTotals              `xml:"valorTotal" custom:"Valor total"`
+}
+
+// TISSHospitalization is the hospitalization of a hospitalization guide (dadosInternacao).
+type TISSHospitalization struct {
+	Character    string `xml:"caraterAtendimento" validate:"required,oneof=1 2" custom:"Caráter do atendimento"`
+	BillingType  string `xml:"tipoFaturamento" validate:"required,oneof=1 2 3 4" custom:"Tipo de faturamento"`
+	BillingStart string `xml:"dataInicioFaturamento" validate:"required,datetime=2006-01-02" custom:"Início do faturamento"`
+	StartTime    string `xml:"horaInicioFaturamento,omitempty" validate:"omitempty,datetime=15:04:05" custom:"Hora de início do faturamento"`
+	BillingEnd   string `xml:"dataFinalFaturamento" validate:"required,datetime=2006-01-02,after_or_equal=BillingStart" custom:"Fim do faturamento"`
+	EndTime      string `xml:"horaFinalFaturamento,omitempty" validate:"omitempty,datetime=15:04:05" custom:"Hora de fim do faturamento"`
+	Type         string `xml:"tipoInternacao" validate:"required,oneof=1 2 3 4 5" custom:"Tipo de internação"`
+	Regime       string `xml:"regimeInternacao" validate:"required,oneof=1 2 3" custom:"Regime de internação"`
+}
+
+// TISSDischarge is the discharge of a hospitalization guide (dadosSaidaInternacao), with up to 4 CID-10 diagnoses.
+type TISSDischarge struct {
+	Diagnoses     []string `xml:"diagnostico" validate:"max=4" custom:"Diagnóstico"`
+	Accident      string   `xml:"indicadorAcidente" validate:"required,oneof=0 1 2 9" custom:"Indicação de acidente"`
+	ClosingReason string   `xml:"motivoEncerramento" validate:"required,len=2,numeric" custom:"Motivo de encerramento"`
+}
+
+// TISSOperatorToProvider is the content of the responses of operators (operadoraParaPrestador).
+type TISSOperatorToProvider struct {
+	BatchReceipt *TISSBatchReceipt `xml:"recebimentoLote"`
+}
+
+// TISSBatchReceipt is the receipt of a batch of guides (recebimentoLote), holding the error of rejected batches.
+type TISSBatchReceipt struct {
+	Error *TISSGlosa `xml:"mensagemErro"`
+}
+
+// TISSGlosa is a reason of rejection (motivoGlosa), coded by the table 38 of the standard.
+type TISSGlosa struct {
+	Code        string `xml:"codigoGlosa"`
+	Description string `xml:"descricaoGlosa"`
+}
+
+// registerTISSRules registers the struct-level rules of the TISS messages, which check the codes of the guides
+// against the reference tables registered with RegisterReferenceTables: ANS registries, CBO occupations, CID-10
+// diagnoses and the TUSS procedures of table 22. Codes are not checked against tables that are not registered.
+// Their violations are translated with the messages of the tags of the tables.
+func (v *Validate) registerTISSRules() {
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		party := sl.Current().Interface().(TISSParty)
+		v.reportUnknownCode(sl, "ans", "ANSRegistry", party.ANSRegistry)
+	}, TISSParty{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		header := sl.Current().Interface().(TISSGuideHeader)
+		v.reportUnknownCode(sl, "ans", "ANSRegistry", header.ANSRegistry)
+	}, TISSGuideHeader{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		professional := sl.Current().Interface().(TISSProfessional)
+		v.reportUnknownCode(sl, "cbo", "CBO", professional.CBO)
+	}, TISSProfessional{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		procedure := sl.Current().Interface().(TISSProcedure)
+		if procedure.Table == "22" {
+			v.reportUnknownCode(sl, "tuss", "Code", procedure.Code)
+		}
+	}, TISSProcedure{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		discharge := sl.Current().Interface().(TISSDischarge)
+		for i, diagnosis := range discharge.Diagnoses {
+			v.reportUnknownCode(sl, "cid10", "Diagnoses["+strconv.Itoa(i)+"]", diagnosis)
+		}
+	}, TISSDischarge{})
+}
+
+// reportUnknownCode reports the field with the tag of the reference table when the table is registered
+// and the code, if any, is not one of its codes.
+func (v *Validate) reportUnknownCode(sl validator.StructLevel, table, field, code string) {
+	if t, ok := v.referenceTables[table]; ok && code != "" && !t.Valid(code) {
+		sl.ReportError(code, field, field, table, "")
+	}
+}
+
+// ParseTISS decodes the TISS message of the request body into out and validates it with v, as DecodeTISSCtx does,
+// translating the violations to the locale of the request and passing the request context to the context-aware rules.
+func ParseTISS(out *TISSMessage, c *fiber.Ctx, v Validator) error {
+	err := DecodeTISSCtx(requestContext{Context: c.UserContext(), c: c}, c.Body(), out, v, RequestLocale(c))
+
+	var validationErrors *ValidationErrors
+	if errors.As(err, &validationErrors) {
+		validationErrors.setDefaultSource(SourceBody)
+	}
+	return err
+}
+
+// DecodeTISS decodes the TISS message into out, checks its root element, verifies its hash and validates it with v,
+// returning a tiss-invalid-message HTTPError whose violations, translated to the locale, are addressed by the path of
+// the elements, e.g. prestadorParaOperadora.loteGuias.guiasTISS.guiaConsulta[0].dadosBeneficiario.numeroCarteira.
+// Messages are decoded from the encoding of their XML declaration, usually ISO-8859-1. Verification stops at the first
+// failing step, so that malformed or corrupted messages are reported by a single violation.
+func DecodeTISS(data []byte, out *TISSMessage, v Validator, locale string) error {
+	return DecodeTISSCtx(context.Background(), data, out, v, locale)
+}
+
+// DecodeTISSCtx decodes and validates the TISS message as DecodeTISS does, passing the context to the context-aware
+// rules registered with RegisterRuleCtx and RegisterStructRuleCtx when v is a ContextValidator.
+func DecodeTISSCtx(ctx context.Context, data []byte, out *TISSMessage, v Validator, locale string) error {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	if violation, ok := decodeTISSMessage(data, out); !ok {
+		return tissError(locale, violation)
+	}
+
+	hash, err := TISSHash(data)
+	if err != nil {
+		return tissError(locale, Violation{Tag: TagTISSSyntax, Param: "1"})
+	}
+	if !strings.EqualFold(strings.TrimSpace(out.Epilogue.Hash), hash) {
+		return tissError(locale, Violation{Field: "epilogo.hash", Tag: TagTISSHash, Value: out.Epilogue.Hash})
+	}
+
+	var validateErr error
+	switch v := v.(type) {
+	case ContextValidator:
+		validateErr = v.StructTranslatedLocaleCtx(ctx, out, locale)
+	case LocalizedValidator:
+		validateErr = v.StructTranslatedLocale(out, locale)
+	default:
+		validateErr = v.StructTranslated(out)
+	}
+
+	var validationErrs *ValidationErrors
+	if errors.As(validateErr, &validationErrs) {
+		return tissError(locale, validationErrs.Violations()...)
+	}
+	return validateErr
+}
+
+// decodeTISSMessage decodes the message into out, returning the violation of malformed messages
+// and of messages whose root element is not a TISS message.
+func decodeTISSMessage(data []byte, out *TISSMessage) (Violation, bool) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = tissCharsetReader
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return tissSyntaxViolation(dec, err), false
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "mensagemTISS" || start.Name.Space != TISSNamespace {
+			return Violation{Field: start.Name.Local, Tag: TagTISSStructure, Param: TISSNamespace}, false
+		}
+		if err := dec.DecodeElement(out, &start); err != nil {
+			return tissSyntaxViolation(dec, err), false
+		}
+		return Violation{}, true
+	}
+}
+
+// tissSyntaxViolation returns the violation of a decoding error, at its line.
+func tissSyntaxViolation(dec *xml.Decoder, err error) Violation {
+	line, _ := dec.InputPos()
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.Line
+	}
+	return Violation{Tag: TagTISSSyntax, Param: strconv.Itoa(line)}
+}
+
+// tissError creates a tiss-invalid-message HTTPError with the violations, translating the messages of the TISS
+// violations to the locale.
+func tissError(locale string, violations ...Violation) *HTTPError {
+	for i, violation := range violations {
+		if messages, ok := tissMessages[violation.Tag]; ok {
+			violations[i].Message = strings.ReplaceAll(localizedMessage(messages, locale), "{0}", violation.Param)
+		}
+		if violation.Tag == TagTISSStructure {
+			violations[i].Param = ""
+		}
+	}
+
+	validationErrs := NewValidationErrors(CodeTISSInvalidMessage.Message(locale))
+	validationErrs.AddViolations(violations...)
+	return CodeTISSInvalidMessage.New(validationErrs)
+}
+
+// TISSHash returns the hash of the TISS message: the MD5, in hexadecimal, of the values of all its elements but the
+// hash itself, concatenated in document order without tags and encoded in the charset of its XML declaration, as
+// written by the sender: ISO-8859-1, Windows-1252 or, for messages without declaration, UTF-8.
+func TISSHash(data []byte) (string, error) {
+	var enc encoding.Encoding
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		var err error
+		enc, err = tissEncoding(charset)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+
+	var content, value strings.Builder
+	leaf := false
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			leaf = token.Name.Local != "hash"
+			value.Reset()
+		case xml.CharData:
+			value.Write(token)
+		case xml.EndElement:
+			if leaf {
+				content.WriteString(value.String())
+			}
+			leaf = false
+		}
+	}
+
+	encoded := []byte(content.String())
+	if enc != nil {
+		var err error
+		if encoded, err = enc.NewEncoder().Bytes(encoded); err != nil {
+			return "", err
+		}
+	}
+	sum := md5.Sum(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// tissCharsetReader decodes the TISS messages declared in ISO-8859-1 or Windows-1252.
+func tissCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := tissEncoding(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// tissEncoding returns the encoding of a charset declared by TISS messages, ISO-8859-1 or Windows-1252.
+func tissEncoding(charset string) (encoding.Encoding, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "iso8859-1", "latin1":
+		return charmap.ISO8859_1, nil
+	case "windows-1252", "cp1252":
+		return charmap.Windows1252, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
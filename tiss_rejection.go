@@ -0,0 +1,160 @@
+// Package kit provides utilities for handling HTTP request parsing and validation.
+// This file defines the rejection of TISS messages: the receipt (recebimentoLote) with the reason of the rejection
+// (mensagemErro) that operators return to providers, with its table 38 code, description and hash.
+
+package kit
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Table 38 codes of the rejections of TISS messages.
+const (
+	TISSGlosaInvalidMessage = "5001" // message out of the TISS standard
+	TISSGlosaUnreadableXML  = "5002" // XML that could not be validated
+	TISSGlosaInvalidHash    = "5014" // invalid hash, the message may be corrupted
+)
+
+// tissGlosaDescriptions are the table 38 descriptions of the rejections of TISS messages.
+var tissGlosaDescriptions = map[string]string{
+	TISSGlosaInvalidMessage: "MENSAGEM ELETRÔNICA FORA DO PADRÃO TISS",
+	TISSGlosaUnreadableXML:  "NÃO FOI POSSÍVEL VALIDAR O ARQUIVO XML",
+	TISSGlosaInvalidHash:    "CÓDIGO HASH INVÁLIDO. MENSAGEM PODE ESTAR CORROMPIDA",
+}
+
+// tissGlosaMaxLength is the maximum length of the description of a rejection (descricaoGlosa).
+const tissGlosaMaxLength = 500
+
+// TISSRejectionConfig configures the rejection rendered by RenderTISSRejection.
+type TISSRejectionConfig struct {
+	Sequence    string          // Sequential number of the rejection transaction. Defaults to the one of the received message.
+	ANSRegistry string          // ANS registry of the operator rejecting the message. Defaults to the destination of the received message.
+	Provider    *TISSProviderID // Provider the rejection is sent to. Defaults to the origin of the received message.
+	Time        time.Time       // Registration time of the rejection transaction. Defaults to the current time.
+}
+
+// RenderTISSRejection renders the rejection of the received TISS message, encoded in ISO-8859-1 with its hash:
+// a PROTOCOLO_RECEBIMENTO transaction from the operator to the provider that sent the message, whose receipt holds the
+// table 38 code of the error returned by DecodeTISS and a description listing its violations. The received message
+// may be nil or partially decoded, as it is for malformed messages; the config then provides the parties and the
+// sequential number of the rejection.
+func RenderTISSRejection(received *TISSMessage, err error, config TISSRejectionConfig) ([]byte, error) {
+	if received == nil {
+		received = &TISSMessage{}
+	}
+	if config.Sequence == "" {
+		config.Sequence = received.Header.Transaction.Sequence
+	}
+	if config.ANSRegistry == "" {
+		config.ANSRegistry = received.Header.Destination.ANSRegistry
+	}
+	if config.Provider == nil {
+		config.Provider = received.Header.Origin.Provider
+	}
+	if config.Time.IsZero() {
+		config.Time = time.Now()
+	}
+	version := received.Header.Version
+	if version == "" {
+		version = TISSVersion
+	}
+
+	code, description := tissGlosa(err)
+	rejection := TISSMessage{
+		Header: TISSHeader{
+			Transaction: TISSTransaction{
+				Type:     "PROTOCOLO_RECEBIMENTO",
+				Sequence: config.Sequence,
+				Date:     config.Time.Format(time.DateOnly),
+				Time:     config.Time.Format(time.TimeOnly),
+			},
+			Origin:      TISSParty{ANSRegistry: config.ANSRegistry},
+			Destination: TISSParty{Provider: config.Provider},
+			Version:     version,
+		},
+		OperatorToProvider: &TISSOperatorToProvider{
+			BatchReceipt: &TISSBatchReceipt{Error: &TISSGlosa{Code: code, Description: description}},
+		},
+	}
+
+	data, err := renderTISSMessage(rejection)
+	if err != nil {
+		return nil, err
+	}
+	if rejection.Epilogue.Hash, err = TISSHash(data); err != nil {
+		return nil, err
+	}
+	return renderTISSMessage(rejection)
+}
+
+// renderTISSMessage renders the TISS message as XML encoded in ISO-8859-1, replacing the characters it lacks by '?'.
+func renderTISSMessage(message TISSMessage) ([]byte, error) {
+	body, err := xml.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte(`<?xml version="1.0" encoding="ISO-8859-1"?>` + "\n")
+	for _, r := range string(body) {
+		if r > 0xff {
+			r = '?'
+		}
+		data = append(data, byte(r))
+	}
+	return data, nil
+}
+
+// SendTISSRejection sends the rejection of the received TISS message rendered by RenderTISSRejection as the response,
+// with the status of the error, 422 for the errors of DecodeTISS.
+func SendTISSRejection(c *fiber.Ctx, received *TISSMessage, err error, config TISSRejectionConfig) error {
+	body, renderErr := RenderTISSRejection(received, err, config)
+	if renderErr != nil {
+		return renderErr
+	}
+
+	status := http.StatusUnprocessableEntity
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Status
+	}
+
+	c.Set(fiber.HeaderContentType, "application/xml; charset=ISO-8859-1")
+	return c.Status(status).Send(body)
+}
+
+// tissGlosa returns the table 38 code of the error and its description, followed by the messages of its violations
+// and truncated to the maximum length of the descriptions.
+func tissGlosa(err error) (string, string) {
+	var violations []Violation
+	var validationErrs *ValidationErrors
+	if errors.As(err, &validationErrs) {
+		violations = validationErrs.Violations()
+	}
+
+	code := TISSGlosaInvalidMessage
+	if len(violations) > 0 {
+		switch violations[0].Tag {
+		case TagTISSSyntax:
+			code = TISSGlosaUnreadableXML
+		case TagTISSHash:
+			code = TISSGlosaInvalidHash
+		}
+	}
+
+	description := tissGlosaDescriptions[code]
+	if messages := violationMessages(violations); len(messages) > 0 {
+		// '›', the separator of label paths, is not available in ISO-8859-1
+		description += ": " + strings.ReplaceAll(strings.Join(messages, "; "), "›", ">")
+	}
+	if utf8.RuneCountInString(description) > tissGlosaMaxLength {
+		description = string([]rune(description)[:tissGlosaMaxLength-3]) + "..."
+	}
+	return code, description
+}
@@ -0,0 +1,129 @@
+package kit_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arvo-health/kit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestRenderTISSRejection(t *testing.T) {
+	v := healthValidator(t)
+	now := time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)
+
+	var received kit.TISSMessage
+	data := tissMessage(t, strings.Replace(tissConsultationGuide, "<ans:atendimentoRN>N", "<ans:atendimentoRN>X", 1))
+	decodeErr := kit.DecodeTISS(data, &received, v, kit.LocalePtBR)
+	require.Error(t, decodeErr)
+
+	body, err := kit.RenderTISSRejection(&received, decodeErr, kit.TISSRejectionConfig{Sequence: "43", Time: now})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), `<?xml version="1.0" encoding="ISO-8859-1"?>`))
+
+	var rejection kit.TISSMessage
+	require.NoError(t, kit.DecodeTISS(body, &rejection, v, kit.LocalePtBR))
+
+	assert.Equal(t, kit.TISSTransaction{
+		Type:     "PROTOCOLO_RECEBIMENTO",
+		Sequence: "43",
+		Date:     "2025-03-10",
+		Time:     "11:00:00",
+	}, rejection.Header.Transaction)
+	assert.Equal(t, "005711", rejection.Header.Origin.ANSRegistry)
+	assert.Equal(t, &kit.TISSProviderID{OperatorCode: "123456"}, rejection.Header.Destination.Provider)
+	assert.Equal(t, "4.01.00", rejection.Header.Version)
+	assert.Equal(t, &kit.TISSGlosa{
+		Code: kit.TISSGlosaInvalidMessage,
+		Description: "MENSAGEM ELETRÔNICA FORA DO PADRÃO TISS: Prestador para operadora > Lote de guias > Guias > " +
+			"Guia de consulta 1 > Beneficiário > Atendimento ao recém-nato deve ser um de [S N]",
+	}, rejection.OperatorToProvider.BatchReceipt.Error)
+}
+
+func TestRenderTISSRejectionGlosas(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode string
+		expectedDesc string
+	}{
+		{
+			name:         "Malformed message",
+			err:          kit.DecodeTISS([]byte("<mensagemTISS"), &kit.TISSMessage{}, healthValidator(t), kit.LocalePtBR),
+			expectedCode: kit.TISSGlosaUnreadableXML,
+			expectedDesc: "NÃO FOI POSSÍVEL VALIDAR O ARQUIVO XML: XML inválido na linha 1",
+		},
+		{
+			name: "Invalid hash",
+			err: kit.CodeTISSInvalidMessage.New(func() error {
+				validationErrs := kit.NewValidationErrors("mensagem TISS inválida")
+				validationErrs.AddViolations(kit.Violation{Tag: kit.TagTISSHash, Message: "hash inválido"})
+				return validationErrs
+			}()),
+			expectedCode: kit.TISSGlosaInvalidHash,
+			expectedDesc: "CÓDIGO HASH INVÁLIDO. MENSAGEM PODE ESTAR CORROMPIDA: hash inválido",
+		},
+		{
+			name:         "Other error",
+			err:          errors.New("boom"),
+			expectedCode: kit.TISSGlosaInvalidMessage,
+			expectedDesc: "MENSAGEM ELETRÔNICA FORA DO PADRÃO TISS",
+		},
+		{
+			name:         "Long description",
+			err:          kit.NewValidationErrors("validation failed", strings.Repeat("a", 600)),
+			expectedCode: kit.TISSGlosaInvalidMessage,
+			expectedDesc: "MENSAGEM ELETRÔNICA FORA DO PADRÃO TISS: " + strings.Repeat("a", 456) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := kit.RenderTISSRejection(nil, tt.err, kit.TISSRejectionConfig{
+				Sequence:    "1",
+				ANSRegistry: "005711",
+				Provider:    &kit.TISSProviderID{CNPJ: "11222333000181"},
+			})
+			require.NoError(t, err)
+
+			var rejection kit.TISSMessage
+			require.NoError(t, kit.DecodeTISS(body, &rejection, healthValidator(t), kit.LocalePtBR))
+			assert.Equal(t, kit.TISSVersion, rejection.Header.Version)
+			assert.Equal(t, tt.expectedCode, rejection.OperatorToProvider.BatchReceipt.Error.Code)
+			assert.Equal(t, tt.expectedDesc, rejection.OperatorToProvider.BatchReceipt.Error.Description)
+		})
+	}
+}
+
+func TestSendTISSRejection(t *testing.T) {
+	v := healthValidator(t)
+
+	app := fiber.New()
+	app.Post("/tiss", func(c *fiber.Ctx) error {
+		var message kit.TISSMessage
+		if err := kit.ParseTISS(&message, c, v); err != nil {
+			return kit.SendTISSRejection(c, &message, err, kit.TISSRejectionConfig{})
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/tiss", strings.NewReader("<mensagemTISS"))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "application/xml; charset=ISO-8859-1", resp.Header.Get(fiber.HeaderContentType))
+
+	body, err := io.ReadAll(charmap.ISO8859_1.NewDecoder().Reader(resp.Body))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "<codigoGlosa>5002</codigoGlosa>")
+	assert.Contains(t, string(body), "<descricaoGlosa>NÃO FOI POSSÍVEL VALIDAR O ARQUIVO XML: XML inválido na linha 1</descricaoGlosa>")
+}
@@ -0,0 +1,418 @@
+package kit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// tissConsultationGuide is a valid consultation guide of a TISS batch.
+const tissConsultationGuide = `
+<ans:guiaConsulta>
+  <ans:cabecalhoConsulta>
+    <ans:registroANS>005711</ans:registroANS>
+    <ans:numeroGuiaPrestador>G-0001</ans:numeroGuiaPrestador>
+  </ans:cabecalhoConsulta>
+  <ans:dadosBeneficiario>
+    <ans:numeroCarteira>0012345678</ans:numeroCarteira>
+    <ans:atendimentoRN>N</ans:atendimentoRN>
+    <ans:nomeBeneficiario>José da Conceição</ans:nomeBeneficiario>
+  </ans:dadosBeneficiario>
+  <ans:contratadoExecutante>
+    <ans:codigoPrestadorNaOperadora>123456</ans:codigoPrestadorNaOperadora>
+  </ans:contratadoExecutante>
+  <ans:CNES>1234567</ans:CNES>
+  <ans:profissionalExecutante>
+    <ans:nomeProfissional>Maria Souza</ans:nomeProfissional>
+    <ans:conselhoProfissional>06</ans:conselhoProfissional>
+    <ans:numeroConselhoProfissional>123456</ans:numeroConselhoProfissional>
+    <ans:UF>35</ans:UF>
+    <ans:CBOS>225125</ans:CBOS>
+  </ans:profissionalExecutante>
+  <ans:indicacaoAcidente>9</ans:indicacaoAcidente>
+  <ans:dadosAtendimento>
+    <ans:dataAtendimento>2025-03-10</ans:dataAtendimento>
+    <ans:tipoConsulta>1</ans:tipoConsulta>
+    <ans:procedimento>
+      <ans:codigoTabela>22</ans:codigoTabela>
+      <ans:codigoProcedimento>10101012</ans:codigoProcedimento>
+      <ans:valorProcedimento>150.00</ans:valorProcedimento>
+    </ans:procedimento>
+  </ans:dadosAtendimento>
+</ans:guiaConsulta>`
+
+// tissMessage returns a TISS batch with the guides, encoded in ISO-8859-1 with its hash.
+func tissMessage(t *testing.T, guides string) []byte {
+	t.Helper()
+
+	message := `<?xml version="1.0" encoding="ISO-8859-1"?>
+<ans:mensagemTISS xmlns:ans="http://www.ans.gov.br/padroes/tiss/schemas">
+  <ans:cabecalho>
+    <ans:identificacaoTransacao>
+      <ans:tipoTransacao>ENVIO_LOTE_GUIAS</ans:tipoTransacao>
+      <ans:sequencialTransacao>42</ans:sequencialTransacao>
+      <ans:dataRegistroTransacao>2025-03-10</ans:dataRegistroTransacao>
+      <ans:horaRegistroTransacao>10:30:00</ans:horaRegistroTransacao>
+    </ans:identificacaoTransacao>
+    <ans:origem>
+      <ans:identificacaoPrestador>
+        <ans:codigoPrestadorNaOperadora>123456</ans:codigoPrestadorNaOperadora>
+      </ans:identificacaoPrestador>
+    </ans:origem>
+    <ans:destino>
+      <ans:registroANS>005711</ans:registroANS>
+    </ans:destino>
+    <ans:Padrao>4.01.00</ans:Padrao>
+  </ans:cabecalho>
+  <ans:prestadorParaOperadora>
+    <ans:loteGuias>
+      <ans:numeroLote>7</ans:numeroLote>
+      <ans:guiasTISS>` + guides + `
+      </ans:guiasTISS>
+    </ans:loteGuias>
+  </ans:prestadorParaOperadora>
+  <ans:epilogo>
+    <ans:hash>HASH</ans:hash>
+  </ans:epilogo>
+</ans:mensagemTISS>`
+
+	data, err := charmap.ISO8859_1.NewEncoder().Bytes([]byte(message))
+	require.NoError(t, err)
+
+	hash, err := kit.TISSHash(data)
+	require.NoError(t, err)
+	return []byte(strings.Replace(string(data), "HASH", hash, 1))
+}
+
+func TestDecodeTISS(t *testing.T) {
+	const guidesPath = "prestadorParaOperadora.loteGuias.guiasTISS."
+	const guidesLabel = "Prestador para operadora › Lote de guias › Guias › "
+
+	tests := []struct {
+		name               string
+		data               func(t *testing.T) []byte
+		locale             string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name: "Valid consultation batch",
+			data: func(t *testing.T) []byte { return tissMessage(t, tissConsultationGuide) },
+		},
+		{
+			name: "Invalid consultation guide",
+			data: func(t *testing.T) []byte {
+				guide := strings.NewReplacer(
+					"<ans:numeroCarteira>0012345678</ans:numeroCarteira>", "<ans:numeroCarteira></ans:numeroCarteira>",
+					"<ans:CBOS>225125</ans:CBOS>", "<ans:CBOS>999999</ans:CBOS>",
+					"<ans:codigoProcedimento>10101012", "<ans:codigoProcedimento>99999999",
+				).Replace(tissConsultationGuide)
+				return tissMessage(t, guide)
+			},
+			expectedViolations: []kit.Violation{
+				{Field: guidesPath + "guiaConsulta[0].dadosBeneficiario.numeroCarteira", Tag: "required", Value: "",
+					Message: guidesLabel + "Guia de consulta 1 › Beneficiário › Número da carteira é um campo obrigatório"},
+				{Field: guidesPath + "guiaConsulta[0].profissionalExecutante.CBOS", Tag: "cbo", Value: "999999",
+					Message: guidesLabel + "Guia de consulta 1 › Profissional executante › CBO deve ser um código CBO válido"},
+				{Field: guidesPath + "guiaConsulta[0].dadosAtendimento.procedimento.codigoProcedimento", Tag: "tuss", Value: "99999999",
+					Message: guidesLabel + "Guia de consulta 1 › Atendimento › Procedimento › Código do procedimento deve ser um código TUSS válido"},
+			},
+		},
+		{
+			name: "Invalid hospitalization guide in English",
+			data: func(t *testing.T) []byte {
+				return tissMessage(t, `
+<ans:guiaResumoInternacao>
+  <ans:cabecalhoGuia>
+    <ans:registroANS>005711</ans:registroANS>
+    <ans:numeroGuiaPrestador>I-0001</ans:numeroGuiaPrestador>
+  </ans:cabecalhoGuia>
+  <ans:numeroGuiaSolicitacaoInternacao>S-0001</ans:numeroGuiaSolicitacaoInternacao>
+  <ans:dadosAutorizacao>
+    <ans:dataAutorizacao>2025-03-01</ans:dataAutorizacao>
+  </ans:dadosAutorizacao>
+  <ans:dadosBeneficiario>
+    <ans:numeroCarteira>0012345678</ans:numeroCarteira>
+    <ans:atendimentoRN>N</ans:atendimentoRN>
+  </ans:dadosBeneficiario>
+  <ans:dadosExecutante>
+    <ans:contratadoExecutante>
+      <ans:cnpjContratado>11222333000181</ans:cnpjContratado>
+    </ans:contratadoExecutante>
+    <ans:CNES>1234567</ans:CNES>
+  </ans:dadosExecutante>
+  <ans:dadosInternacao>
+    <ans:caraterAtendimento>2</ans:caraterAtendimento>
+    <ans:tipoFaturamento>4</ans:tipoFaturamento>
+    <ans:dataInicioFaturamento>2025-03-05</ans:dataInicioFaturamento>
+    <ans:dataFinalFaturamento>2025-03-01</ans:dataFinalFaturamento>
+    <ans:tipoInternacao>1</ans:tipoInternacao>
+    <ans:regimeInternacao>1</ans:regimeInternacao>
+  </ans:dadosInternacao>
+  <ans:dadosSaidaInternacao>
+    <ans:diagnostico>A09</ans:diagnostico>
+    <ans:diagnostico>Z999</ans:diagnostico>
+    <ans:indicadorAcidente>9</ans:indicadorAcidente>
+    <ans:motivoEncerramento>12</ans:motivoEncerramento>
+  </ans:dadosSaidaInternacao>
+  <ans:procedimentosExecutados>
+    <ans:procedimentoExecutado>
+      <ans:dataExecucao>2025-03-05</ans:dataExecucao>
+      <ans:procedimento>
+        <ans:codigoTabela>22</ans:codigoTabela>
+        <ans:codigoProcedimento>10102019</ans:codigoProcedimento>
+      </ans:procedimento>
+      <ans:quantidadeExecutada>0</ans:quantidadeExecutada>
+      <ans:valorUnitario>80.00</ans:valorUnitario>
+      <ans:valorTotal>0</ans:valorTotal>
+    </ans:procedimentoExecutado>
+  </ans:procedimentosExecutados>
+  <ans:valorTotal>
+    <ans:valorTotalGeral>0</ans:valorTotalGeral>
+  </ans:valorTotal>
+</ans:guiaResumoInternacao>`)
+			},
+			locale: kit.LocaleEn,
+			expectedViolations: []kit.Violation{
+				{Field: guidesPath + "guiaResumoInternacao[0].dadosInternacao.dataFinalFaturamento", Tag: "after_or_equal",
+					Param: "BillingStart", Value: "2025-03-01",
+					Message: guidesLabel + "Guia de resumo de internação 1 › Internação › Fim do faturamento must be on or after Início do faturamento"},
+				{Field: guidesPath + "guiaResumoInternacao[0].dadosSaidaInternacao.diagnostico[1]", Tag: "cid10", Value: "Z999",
+					Message: guidesLabel + "Guia de resumo de internação 1 › Saída › Diagnóstico 2 must be a valid ICD-10 (CID-10) code"},
+				{Field: guidesPath + "guiaResumoInternacao[0].procedimentoExecutado[0].quantidadeExecutada", Tag: "gt", Param: "0",
+					Value:   float64(0),
+					Message: guidesLabel + "Guia de resumo de internação 1 › Procedimento executado 1 › Quantidade executada must be greater than 0"},
+			},
+		},
+		{
+			name: "Invalid hash",
+			data: func(t *testing.T) []byte {
+				data := tissMessage(t, tissConsultationGuide)
+				hash, err := kit.TISSHash(data)
+				require.NoError(t, err)
+				return []byte(strings.Replace(string(data), hash, "0123456789abcdef0123456789abcdef", 1))
+			},
+			expectedViolations: []kit.Violation{
+				{Field: "epilogo.hash", Tag: kit.TagTISSHash, Value: "0123456789abcdef0123456789abcdef",
+					Message: "o hash não corresponde ao conteúdo da mensagem"},
+			},
+		},
+		{
+			name: "Malformed XML",
+			data: func(t *testing.T) []byte {
+				return []byte("<?xml version=\"1.0\"?>\n<ans:mensagemTISS xmlns:ans=\"" + kit.TISSNamespace + "\">\n<ans:cabecalho>")
+			},
+			locale: kit.LocaleEs,
+			expectedViolations: []kit.Violation{
+				{Tag: kit.TagTISSSyntax, Param: "3", Message: "XML inválido en la línea 3"},
+			},
+		},
+		{
+			name: "Not a TISS message",
+			data: func(t *testing.T) []byte {
+				return []byte(`<mensagemTISS><cabecalho/></mensagemTISS>`)
+			},
+			expectedViolations: []kit.Violation{
+				{Field: "mensagemTISS", Tag: kit.TagTISSStructure,
+					Message: "o elemento raiz deve ser mensagemTISS do namespace http://www.ans.gov.br/padroes/tiss/schemas"},
+			},
+		},
+	}
+
+	v := healthValidator(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message kit.TISSMessage
+			err := kit.DecodeTISS(tt.data(t), &message, v, tt.locale)
+			if tt.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, "tiss-invalid-message", httpError.Slug)
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedViolations, validationErr.Violations())
+		})
+	}
+}
+
+func TestDecodeTISSMessage(t *testing.T) {
+	var message kit.TISSMessage
+	require.NoError(t, kit.DecodeTISS(tissMessage(t, tissConsultationGuide), &message, healthValidator(t), ""))
+
+	assert.Equal(t, "ENVIO_LOTE_GUIAS", message.Header.Transaction.Type)
+	assert.Equal(t, "123456", message.Header.Origin.Provider.OperatorCode)
+	assert.Equal(t, "005711", message.Header.Destination.ANSRegistry)
+	require.NotNil(t, message.ProviderToOperator)
+	require.NotNil(t, message.ProviderToOperator.Batch)
+
+	batch := message.ProviderToOperator.Batch
+	assert.Equal(t, "7", batch.Number)
+	require.Len(t, batch.Guides.Consultations, 1)
+
+	guide := batch.Guides.Consultations[0]
+	assert.Equal(t, "José da Conceição", guide.Beneficiary.Name)
+	assert.Equal(t, kit.TISSProcedure{Table: "22", Code: "10101012", Value: 150}, guide.Care.Procedure)
+}
+
+func TestDecodeTISSWithRegisteredReferenceTables(t *testing.T) {
+	guide := strings.Replace(tissConsultationGuide, "<ans:codigoProcedimento>10101012", "<ans:codigoProcedimento>99999999", 1)
+
+	tuss := kit.NewTUSSTable()
+	require.NoError(t, tuss.Load(strings.NewReader("99999999;Procedimento local\n")))
+	v := kit.NewValidator()
+	require.NoError(t, v.RegisterReferenceTables(tuss))
+
+	var message kit.TISSMessage
+	require.NoError(t, kit.DecodeTISS(tissMessage(t, guide), &message, v, kit.LocalePtBR),
+		"Codes are checked against the tables registered in place of the embedded ones")
+	assert.Equal(t, "99999999", message.ProviderToOperator.Batch.Guides.Consultations[0].Care.Procedure.Code)
+}
+
+func TestDecodeTISSGuidesOfMoreThanOneType(t *testing.T) {
+	spsadt := strings.ReplaceAll(tissConsultationGuide, "guiaConsulta", "guiaSP-SADT")
+
+	var message kit.TISSMessage
+	err := kit.DecodeTISS(tissMessage(t, tissConsultationGuide+spsadt), &message, healthValidator(t), kit.LocalePtBR)
+
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	violation := validationErr.Violations()[0]
+	assert.Equal(t, "prestadorParaOperadora.loteGuias.guiasTISS.guiaConsulta", violation.Field)
+	assert.Equal(t, "Prestador para operadora › Lote de guias › Guias › Guia de consulta não pode ser informado junto com "+
+		"Guia SP/SADT, Guia de resumo de internação", violation.Message)
+}
+
+func TestParseTISS(t *testing.T) {
+	v := healthValidator(t)
+
+	var message kit.TISSMessage
+	err := parseRoute(t, fiber.MethodPost, "/contracts/1", string(tissMessage(t, tissConsultationGuide)), nil,
+		func(c *fiber.Ctx) error {
+			return kit.ParseTISS(&message, c, v)
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "42", message.Header.Transaction.Sequence)
+
+	err = parseRoute(t, fiber.MethodPost, "/contracts/1", "<mensagemTISS", nil, func(c *fiber.Ctx) error {
+		return kit.ParseTISS(&message, c, v)
+	})
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, kit.SourceBody, validationErr.Violations()[0].Source)
+}
+
+func TestParseTISSWithUserContext(t *testing.T) {
+	v := healthValidator(t)
+	err := v.RegisterStructRuleCtx(func(ctx context.Context, sl validator.StructLevel) {
+		provider := sl.Current().Interface().(kit.TISSProviderID)
+		if company, _ := kit.UserCompany(ctx); company != provider.OperatorCode {
+			sl.ReportError(provider.OperatorCode, "OperatorCode", "OperatorCode", "own_provider", "")
+		}
+	}, map[string]map[string]string{
+		"own_provider": {kit.LocalePtBR: "{0} deve ser o do prestador do usuário"},
+	}, kit.TISSProviderID{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		company            string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:    "Provider of the user",
+			company: "123456",
+		},
+		{
+			name:    "Provider of another user",
+			company: "654321",
+			expectedViolations: []kit.Violation{
+				{Field: "cabecalho.origem.identificacaoPrestador.codigoPrestadorNaOperadora", Tag: "own_provider",
+					Value: "123456", Source: kit.SourceBody,
+					Message: "Cabeçalho › Origem › Prestador › Código na operadora deve ser o do prestador do usuário"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message kit.TISSMessage
+			err := parseRoute(t, fiber.MethodPost, "/contracts/1", string(tissMessage(t, tissConsultationGuide)), nil,
+				func(c *fiber.Ctx) error {
+					c.Locals(kit.CtxKeyUserCompany, tt.company)
+					return kit.ParseTISS(&message, c, v)
+				})
+			if tt.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedViolations, validationErr.Violations())
+		})
+	}
+}
+
+func TestTISSHash(t *testing.T) {
+	// The reference hash of the consultation batch was computed outside the package, as the standard describes:
+	// the MD5 of the values of the message but the hash, concatenated in document order and encoded in ISO-8859-1,
+	// i.e. of "ENVIO_LOTE_GUIAS422025-03-1010:30:00123456005711" ... "2025-03-1012210101012150.00".
+	const referenceHash = "ed91fb0fd4dcd9eceb9460967e1b565d"
+	reference := tissMessage(t, tissConsultationGuide)
+	assert.Contains(t, string(reference), "<ans:hash>"+referenceHash+"</ans:hash>")
+	hash, err := kit.TISSHash(reference)
+	require.NoError(t, err)
+	assert.Equal(t, referenceHash, hash)
+
+	// The values are hashed as written by the sender, in the charset of the declaration: the expected hashes are
+	// the ones of md5sum over "1Jos\xe9", "1José" and "Pre\x80o \x93novo\x94 \x8a".
+	tests := []struct {
+		name         string
+		data         string
+		expectedHash string
+	}{
+		{
+			name:         "ISO-8859-1",
+			data:         "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><a><b>1</b><c>Jos\xe9</c><hash>ignored</hash></a>",
+			expectedHash: "8df2ea593ece3f76c7fabe7e8d9e3879",
+		},
+		{
+			name:         "Indented",
+			data:         "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<a>\n  <b>1</b>\n  <c>Jos\xe9</c>\n  <hash></hash>\n</a>",
+			expectedHash: "8df2ea593ece3f76c7fabe7e8d9e3879",
+		},
+		{
+			name:         "UTF-8 without declaration",
+			data:         "<a><b>1</b><c>José</c><hash>ignored</hash></a>",
+			expectedHash: "4e6354ee704879e3a68ac2ebd7cf8607",
+		},
+		{
+			name:         "Windows-1252 characters missing from ISO-8859-1",
+			data:         "<?xml version=\"1.0\" encoding=\"windows-1252\"?><a><b>Pre\x80o \x93novo\x94 \x8a</b><hash/></a>",
+			expectedHash: "35d5d30a703970d8ac1a7a5c580cddbf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := kit.TISSHash([]byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHash, hash)
+		})
+	}
+
+	_, err = kit.TISSHash([]byte(`<a><b>`))
+	assert.Error(t, err)
+}
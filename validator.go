@@ -1,30 +1,59 @@
 // Package kit provides struct validation utilities using `go-playground/validator`.
-// This file defines a validation wrapper with support for localized (pt_BR) error messages,
+// This file defines a validation wrapper with support for localized (pt_BR, en and es) error messages,
 // including initialization of a universal translator and custom error message handling.
 
 package kit
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"reflect"
+	"strings"
+	"sync"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
 	"github.com/go-playground/locales/pt_BR"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	esTranslations "github.com/go-playground/validator/v10/translations/es"
 	brTranslations "github.com/go-playground/validator/v10/translations/pt_BR"
 )
 
-// Validate is a struct that wraps a validator instance and a translator for pt_BR localized error messages.
+// Validate is a struct that wraps a validator instance and the translators for localized error messages.
+// The embedded Translator is the one of the DefaultLocale (pt_BR).
 type Validate struct {
 	*validator.Validate // The underlying validation engine.
-	ut.Translator       // Translator for localized error messages.
+	ut.Translator       // Translator for localized error messages in the DefaultLocale.
+
+	translators map[string]ut.Translator // Translators indexed by locale.
+
+	optionalTypes map[reflect.Type]struct{} // Optional types registered with RegisterOptional.
+	checkedTypes  sync.Map                  // Types whose Optional types are checked, mapped to the error of the unregistered ones.
+
+	referenceTables map[string]*ReferenceTable // Reference tables registered with RegisterReferenceTables, by name.
+	templateTags    map[string]struct{}        // Tags translated by templates receiving the field as {0}.
+
+	messageKeys map[string]func(context.Context, validator.FieldError) string // Tags whose translation key depends on the field error.
+}
+
+// LocalizedValidator is implemented by validators able to translate their messages to a given locale.
+// ParseRequestBody uses it to translate validation messages to the locale negotiated for the request.
+type LocalizedValidator interface {
+	Validator
+	StructTranslatedLocale(s interface{}, locale string) error
 }
 
-// NewValidator initializes and returns a new Validate struct with a pt_BR translator and a custom tag name function.
+// NewValidator initializes and returns a new Validate struct with pt_BR, en and es translators
+// and a custom tag name function.
 func NewValidator() *Validate {
-	ptBR := pt_BR.New()
-	uni := ut.New(ptBR)
-	trans, _ := uni.GetTranslator("pt_br")
+	uni := ut.New(pt_BR.New(), pt_BR.New(), en.New(), es.New())
+	ptBR, _ := uni.GetTranslator(LocalePtBR)
+	enUS, _ := uni.GetTranslator(LocaleEn)
+	esES, _ := uni.GetTranslator(LocaleEs)
 
 	validate := validator.New(validator.WithRequiredStructEnabled()) // Initialize the validator instance.
 
@@ -38,30 +67,304 @@ func NewValidator() *Validate {
 		return name
 	})
 
-	// Register Portuguese translations for validation errors.
-	_ = brTranslations.RegisterDefaultTranslations(validate, trans)
+	// Register the default translations of each locale for validation errors.
+	_ = brTranslations.RegisterDefaultTranslations(validate, ptBR)
+	_ = enTranslations.RegisterDefaultTranslations(validate, enUS)
+	_ = esTranslations.RegisterDefaultTranslations(validate, esES)
 
-	return &Validate{
+	v := &Validate{
 		Validate:   validate,
-		Translator: trans,
+		Translator: ptBR,
+		translators: map[string]ut.Translator{
+			LocalePtBR: ptBR,
+			LocaleEn:   enUS,
+			LocaleEs:   esES,
+		},
+		optionalTypes:   map[reflect.Type]struct{}{},
+		referenceTables: map[string]*ReferenceTable{},
+		templateTags:    map[string]struct{}{},
+		messageKeys:     map[string]func(context.Context, validator.FieldError) string{},
+	}
+
+	// Register the built-in Optional types and validation tags.
+	v.registerBuiltinOptionals()
+	v.registerStringRules(brazilianRules)
+	v.registerStringRules(healthRules)
+	_ = v.RegisterReferenceTables(CID10Codes, TUSSCodes, CBOCodes, ANSOperators)
+	v.registerFileRules()
+	v.registerContextRules()
+	v.registerCrossFieldRules()
+	v.registerTISSRules()
+
+	return v
+}
+
+// RegisterRule registers a field-level validation tag together with its message templates per locale, so that
+// custom rules are translated like the built-in ones. Templates receive the field name as {0} and the tag param
+// as {1}, e.g. "{0} deve ter no máximo {1} itens". Locales without a template fall back to the DefaultLocale one,
+// which is required. When callEvenIfNull is true, fn is also called for nil fields.
+func (v *Validate) RegisterRule(tag string, fn validator.Func, messages map[string]string, callEvenIfNull ...bool) error {
+	if err := requireDefaultMessage(tag, messages); err != nil {
+		return err
+	}
+
+	if err := v.RegisterValidation(tag, fn, callEvenIfNull...); err != nil {
+		return fmt.Errorf("validation tag %q: %w", tag, err)
+	}
+
+	return v.registerTranslations(tag, messages)
+}
+
+// RegisterStructRule registers a struct-level validation for the given types, together with the message templates
+// of each tag it reports through validator.StructLevel.ReportError, indexed by tag and then by locale.
+// Templates follow the same rules as the ones of RegisterRule.
+func (v *Validate) RegisterStructRule(fn validator.StructLevelFunc, messages map[string]map[string]string, types ...interface{}) error {
+	return v.registerStructRule(func() { v.RegisterStructValidation(fn, types...) }, messages)
+}
+
+// registerStructRule checks the messages of a struct-level validation, registers it and then its translations.
+func (v *Validate) registerStructRule(register func(), messages map[string]map[string]string) error {
+	var errs []error
+	for tag, tagMessages := range messages {
+		errs = append(errs, requireDefaultMessage(tag, tagMessages))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	register()
+
+	for tag, tagMessages := range messages {
+		errs = append(errs, v.registerTranslations(tag, tagMessages))
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterAliasRule registers an alias for a set of validation tags, e.g. "iscolor" for "hexcolor|rgb|rgba",
+// together with its message templates per locale. Errors of aliased tags are reported, and translated,
+// with the alias. Templates follow the same rules as the ones of RegisterRule.
+func (v *Validate) RegisterAliasRule(alias, tags string, messages map[string]string) (err error) {
+	if err := requireDefaultMessage(alias, messages); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil { // validator panics on restricted alias names
+			err = fmt.Errorf("validation tag %q: %v", alias, r)
+		}
+	}()
+	v.RegisterAlias(alias, tags)
+
+	return v.registerTranslations(alias, messages)
+}
+
+// requireDefaultMessage returns an error if the messages of the tag do not declare the DefaultLocale template.
+func requireDefaultMessage(tag string, messages map[string]string) error {
+	if messages[DefaultLocale] == "" {
+		return fmt.Errorf("validation tag %q: a %s message is required", tag, DefaultLocale)
+	}
+	return nil
+}
+
+// registerTranslations registers the message templates of the tag for every supported locale,
+// falling back to the DefaultLocale template. Templates receive the field name as {0} and the tag param as {1}.
+func (v *Validate) registerTranslations(tag string, messages map[string]string) error {
+	v.templateTags[tag] = struct{}{}
+
+	var errs []error
+	for locale, trans := range v.translators {
+		message := localizedMessage(messages, locale)
+		err := v.RegisterTranslation(tag, trans,
+			func(t ut.Translator) error {
+				return t.Add(tag, message, true)
+			},
+			func(t ut.Translator, fe validator.FieldError) string {
+				msg, err := t.T(v.messageKey(context.Background(), fe), fe.Field(), fe.Param())
+				if err != nil {
+					return fe.Error()
+				}
+				return msg
+			},
+		)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("validation tag %q: %s translation: %w", tag, locale, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TranslatorFor returns the translator of the locale, falling back to the DefaultLocale translator.
+func (v *Validate) TranslatorFor(locale string) ut.Translator {
+	if trans, ok := v.translators[locale]; ok {
+		return trans
 	}
+	return v.Translator
 }
 
 // StructTranslated validates the given struct and returns translated validation error messages if any validation fails.
+// Messages are translated to the DefaultLocale.
 func (v *Validate) StructTranslated(s interface{}) error {
-	err := v.Struct(s)
+	return v.StructTranslatedLocale(s, DefaultLocale)
+}
+
+// StructTranslatedLocale validates the given struct and returns validation error messages translated to the locale,
+// falling back to the DefaultLocale for unsupported locales. Messages name the field by its path: the composed
+// `custom` labels (e.g. Beneficiário 4 › Documento) or, for fields without a label, the JSON path
+// (e.g. beneficiaries[3].document), and the messages of cross-field tags name the referenced fields by their labels.
+// Optional fields are only validated when present.
+func (v *Validate) StructTranslatedLocale(s interface{}, locale string) error {
+	return v.StructTranslatedLocaleCtx(context.Background(), s, locale)
+}
+
+// StructTranslatedCtx validates the given struct as StructTranslated does, passing the context to the
+// context-aware rules registered with RegisterRuleCtx and RegisterStructRuleCtx.
+func (v *Validate) StructTranslatedCtx(ctx context.Context, s interface{}) error {
+	return v.StructTranslatedLocaleCtx(ctx, s, DefaultLocale)
+}
+
+// StructTranslatedLocaleCtx validates the given struct as StructTranslatedLocale does, passing the context to the
+// context-aware rules registered with RegisterRuleCtx and RegisterStructRuleCtx.
+func (v *Validate) StructTranslatedLocaleCtx(ctx context.Context, s interface{}, locale string) error {
+	if err := v.checkOptionalTypes(reflect.TypeOf(s)); err != nil {
+		return err
+	}
+
+	ctx = withFilePagesCache(ctx)
+	err := v.StructCtx(ctx, s)
 	if err == nil {
 		return nil // No validation errors.
 	}
 
 	var validationErrors validator.ValidationErrors
 	if errors.As(err, &validationErrors) {
-		validations := make([]string, 0, len(validationErrors))
-		translations := validationErrors.Translate(v.Translator)
-		for _, t := range translations {
-			validations = append(validations, t)
+		validationErrors = presentFieldErrors(validationErrors)
+		if len(validationErrors) == 0 {
+			return nil // Only absent or null Optional fields failed.
+		}
+
+		trans := v.TranslatorFor(locale)
+		root := reflect.TypeOf(s)
+		violations := make([]Violation, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			path := labelPath(root, fe.StructNamespace())
+			message, ok := translateCrossField(fe, trans, root, path)
+			if !ok {
+				message = v.translateWithPath(ctx, fe, trans, path)
+			}
+
+			violations = append(violations, Violation{
+				Field:   jsonPath(root, fe.StructNamespace()),
+				Source:  requestSource(root, fe.StructNamespace()),
+				Tag:     fe.Tag(),
+				Param:   fe.Param(),
+				Value:   violationValue(fe.Value()),
+				Message: message,
+			})
 		}
-		return NewValidationErrors("validation failed", validations...)
+
+		validationErrs := NewValidationErrors("validation failed")
+		validationErrs.AddViolations(violations...)
+		return validationErrs
 	}
 	return err
 }
+
+// translateWithPath translates the field error, naming the field by its path instead of its own name.
+// The templates of the tags registered with their messages receive the path as {0}, while the messages of the
+// default translations, whose templates all start with the field name, have that name replaced by the path.
+func (v *Validate) translateWithPath(ctx context.Context, fe validator.FieldError, trans ut.Translator, path string) string {
+	if _, ok := v.templateTags[fe.Tag()]; ok {
+		if message, err := trans.T(v.messageKey(ctx, fe), path, fe.Param()); err == nil {
+			return message
+		}
+	}
+
+	message := fe.Translate(trans)
+	if name := fe.Field(); name != "" && strings.HasPrefix(message, name) {
+		message = path + strings.TrimPrefix(message, name)
+	}
+	return message
+}
+
+// messageKey returns the translation key of the message of the field error, its tag unless the tag
+// chooses the key per field error, e.g. file_max_pages for PDFs whose pages cannot be counted, reading what the
+// rules stored in the context of the validation.
+func (v *Validate) messageKey(ctx context.Context, fe validator.FieldError) string {
+	if key, ok := v.messageKeys[fe.Tag()]; ok {
+		return key(ctx, fe)
+	}
+	return fe.Tag()
+}
+
+// jsonPath converts a validator struct namespace (e.g. Order.Items[2].CPF) into the
+// JSON path of the field (e.g. items[2].cpf), following the `json` tags of the root type.
+// Embedded structs without a JSON name are flattened, as encoding/json does.
+func jsonPath(root reflect.Type, structNamespace string) string {
+	segments := fieldPath(root, structNamespace)
+	if segments == nil {
+		return structNamespace
+	}
+
+	var path strings.Builder
+	for _, segment := range segments {
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(segment.name)
+		for _, index := range segment.indexes {
+			path.WriteString("[" + index + "]")
+		}
+	}
+	return path.String()
+}
+
+// splitNamespace splits a validator namespace on dots, ignoring dots inside map keys.
+func splitNamespace(namespace string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i := 0; i < len(namespace); i++ {
+		switch namespace[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, namespace[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, namespace[start:])
+}
+
+// indirectType dereferences pointer types until a non-pointer type is reached.
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// violationValue returns the rejected value reported in a violation. Uploaded files are reported by their name,
+// and null Optional fields as nil.
+func violationValue(value any) any {
+	switch v := value.(type) {
+	case multipart.FileHeader:
+		return v.Filename
+	case *multipart.FileHeader:
+		if v == nil {
+			return nil
+		}
+		return v.Filename
+	case *nullOptional:
+		return nil
+	case []*multipart.FileHeader:
+		names := make([]string, 0, len(v))
+		for _, fh := range v {
+			names = append(names, fh.Filename)
+		}
+		return names
+	}
+	return value
+}
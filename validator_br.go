@@ -0,0 +1,234 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines the built-in validation tags for Brazilian documents (cpf, cnpj, cep, cns and br_phone)
+// along with their translated messages.
+
+package kit
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	cpfRegex   = regexp.MustCompile(`^\d{3}\.?\d{3}\.?\d{3}-?\d{2}$`)
+	cnpjRegex  = regexp.MustCompile(`^[0-9A-Z]{2}\.?[0-9A-Z]{3}\.?[0-9A-Z]{3}/?[0-9A-Z]{4}-?\d{2}$`)
+	cepRegex   = regexp.MustCompile(`^\d{5}-?\d{3}$`)
+	cnsRegex   = regexp.MustCompile(`^[1-2789]\d{2} ?\d{4} ?\d{4} ?\d{4}$`)
+	phoneRegex = regexp.MustCompile(`^(\+?55)?\(?\d{2}\)?9?\d{4}-?\d{4}$`)
+
+	// validDDDs are the Brazilian area codes in use.
+	validDDDs = map[string]struct{}{
+		"11": {}, "12": {}, "13": {}, "14": {}, "15": {}, "16": {}, "17": {}, "18": {}, "19": {},
+		"21": {}, "22": {}, "24": {}, "27": {}, "28": {},
+		"31": {}, "32": {}, "33": {}, "34": {}, "35": {}, "37": {}, "38": {},
+		"41": {}, "42": {}, "43": {}, "44": {}, "45": {}, "46": {}, "47": {}, "48": {}, "49": {},
+		"51": {}, "53": {}, "54": {}, "55": {},
+		"61": {}, "62": {}, "63": {}, "64": {}, "65": {}, "66": {}, "67": {}, "68": {}, "69": {},
+		"71": {}, "73": {}, "74": {}, "75": {}, "77": {}, "79": {},
+		"81": {}, "82": {}, "83": {}, "84": {}, "85": {}, "86": {}, "87": {}, "88": {}, "89": {},
+		"91": {}, "92": {}, "93": {}, "94": {}, "95": {}, "96": {}, "97": {}, "98": {}, "99": {},
+	}
+)
+
+// stringRule is a built-in validation tag applied to string fields, with its messages per locale.
+type stringRule struct {
+	tag      string
+	fn       func(string) bool
+	messages map[string]string
+}
+
+// brazilianRules are the built-in validation tags for Brazilian documents.
+var brazilianRules = []stringRule{
+	{
+		tag: "cpf",
+		fn:  isCPF,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um CPF válido",
+			LocaleEn:   "{0} must be a valid CPF",
+			LocaleEs:   "{0} debe ser un CPF válido",
+		},
+	},
+	{
+		tag: "cnpj",
+		fn:  isCNPJ,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um CNPJ válido",
+			LocaleEn:   "{0} must be a valid CNPJ",
+			LocaleEs:   "{0} debe ser un CNPJ válido",
+		},
+	},
+	{
+		tag: "cep",
+		fn:  isCEP,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um CEP válido",
+			LocaleEn:   "{0} must be a valid CEP",
+			LocaleEs:   "{0} debe ser un CEP válido",
+		},
+	},
+	{
+		tag: "cns",
+		fn:  isCNS,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um Cartão Nacional de Saúde válido",
+			LocaleEn:   "{0} must be a valid National Health Card (CNS) number",
+			LocaleEs:   "{0} debe ser una Tarjeta Nacional de Salud (CNS) válida",
+		},
+	},
+	{
+		tag: "br_phone",
+		fn:  isBrazilianPhone,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um telefone válido",
+			LocaleEn:   "{0} must be a valid Brazilian phone number",
+			LocaleEs:   "{0} debe ser un teléfono brasileño válido",
+		},
+	},
+}
+
+// registerStringRules registers the built-in validation tags and their translations.
+// Fields of any kind other than string are reported as invalid.
+func (v *Validate) registerStringRules(rules []stringRule) {
+	for _, rule := range rules {
+		fn := rule.fn
+		_ = v.RegisterRule(rule.tag, func(fl validator.FieldLevel) bool {
+			return fl.Field().Kind() == reflect.String && fn(fl.Field().String())
+		}, rule.messages)
+	}
+}
+
+// isCPF reports whether s is a valid CPF, formatted (000.000.000-00) or digits only.
+func isCPF(s string) bool {
+	if !cpfRegex.MatchString(s) {
+		return false
+	}
+
+	digits := onlyDigits(s)
+	if allSame(digits) {
+		return false
+	}
+
+	return checkDigit(digits[:9], 10) == digits[9] &&
+		checkDigit(digits[:10], 11) == digits[10]
+}
+
+// isCNPJ reports whether s is a valid CNPJ, formatted (00.000.000/0000-00) or not, including the
+// alphanumeric format, where the first 12 characters may be uppercase letters worth their ASCII code minus 48.
+func isCNPJ(s string) bool {
+	s = strings.ToUpper(s)
+	if !cnpjRegex.MatchString(s) {
+		return false
+	}
+
+	chars := strings.Map(func(r rune) rune {
+		if r == '.' || r == '/' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+	if allSame(chars) {
+		return false
+	}
+
+	return cnpjCheckDigit(chars[:12]) == chars[12] &&
+		cnpjCheckDigit(chars[:13]) == chars[13]
+}
+
+// isCEP reports whether s is a valid CEP, formatted (00000-000) or digits only.
+func isCEP(s string) bool {
+	return cepRegex.MatchString(s) && onlyDigits(s) != "00000000"
+}
+
+// isCNS reports whether s is a valid Cartão Nacional de Saúde number, with or without spaces.
+// Definitive numbers start with 1 or 2 and provisional ones with 7, 8 or 9; both have a weighted
+// sum of digits divisible by 11.
+func isCNS(s string) bool {
+	if !cnsRegex.MatchString(s) {
+		return false
+	}
+
+	digits := onlyDigits(s)
+	sum := 0
+	for i, d := range digits {
+		sum += int(d-'0') * (15 - i)
+	}
+	return sum%11 == 0
+}
+
+// isBrazilianPhone reports whether s is a valid Brazilian landline or mobile phone number, optionally
+// prefixed by the +55 country code and formatted with parentheses, spaces or hyphens.
+// Mobile numbers have 9 digits starting with 9, and landlines 8 digits starting with 2 to 5.
+func isBrazilianPhone(s string) bool {
+	compact := strings.NewReplacer(" ", "", "\t", "").Replace(s)
+	if !phoneRegex.MatchString(compact) {
+		return false
+	}
+
+	digits := onlyDigits(compact)
+	if len(digits) > 11 {
+		digits = strings.TrimPrefix(digits, "55")
+	}
+
+	if _, ok := validDDDs[digits[:2]]; !ok {
+		return false
+	}
+
+	number := digits[2:]
+	switch len(number) {
+	case 9:
+		return number[0] == '9'
+	case 8:
+		return number[0] >= '2' && number[0] <= '5'
+	default:
+		return false
+	}
+}
+
+// checkDigit computes the modulo 11 check digit of the CPF digits, with weights decreasing from firstWeight.
+func checkDigit(digits string, firstWeight int) byte {
+	sum := 0
+	for i, d := range digits {
+		sum += int(d-'0') * (firstWeight - i)
+	}
+	return mod11Digit(sum)
+}
+
+// cnpjCheckDigit computes the modulo 11 check digit of the CNPJ characters, with weights cycling from 2 to 9
+// right to left. Characters are worth their ASCII code minus 48, so digits keep their value.
+func cnpjCheckDigit(chars string) byte {
+	sum := 0
+	weight := 2
+	for i := len(chars) - 1; i >= 0; i-- {
+		sum += int(chars[i]-'0') * weight
+		if weight++; weight > 9 {
+			weight = 2
+		}
+	}
+	return mod11Digit(sum)
+}
+
+// mod11Digit converts a weighted sum into its modulo 11 check digit character.
+func mod11Digit(sum int) byte {
+	if rest := sum % 11; rest >= 2 {
+		return byte('0' + 11 - rest)
+	}
+	return '0'
+}
+
+// onlyDigits removes every non-digit character from s.
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// allSame reports whether all characters of s are equal, a pattern that passes check digits but is invalid.
+func allSame(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
@@ -0,0 +1,119 @@
+package kit_test
+
+import (
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrazilianValidationTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		tag   string
+		value string
+		valid bool
+	}{
+		{name: "CPF digits only", tag: "cpf", value: "52998224725", valid: true},
+		{name: "CPF formatted", tag: "cpf", value: "529.982.247-25", valid: true},
+		{name: "CPF wrong check digit", tag: "cpf", value: "529.982.247-24", valid: false},
+		{name: "CPF repeated digits", tag: "cpf", value: "111.111.111-11", valid: false},
+		{name: "CPF wrong length", tag: "cpf", value: "5299822472", valid: false},
+		{name: "CPF with letters", tag: "cpf", value: "5299822472A", valid: false},
+
+		{name: "CNPJ digits only", tag: "cnpj", value: "11222333000181", valid: true},
+		{name: "CNPJ formatted", tag: "cnpj", value: "11.222.333/0001-81", valid: true},
+		{name: "CNPJ alphanumeric", tag: "cnpj", value: "12.ABC.345/01DE-35", valid: true},
+		{name: "CNPJ alphanumeric unformatted lowercase", tag: "cnpj", value: "12abc34501de35", valid: true},
+		{name: "CNPJ wrong check digit", tag: "cnpj", value: "11.222.333/0001-82", valid: false},
+		{name: "CNPJ alphanumeric wrong check digit", tag: "cnpj", value: "12.ABC.345/01DE-36", valid: false},
+		{name: "CNPJ letters in check digits", tag: "cnpj", value: "12.ABC.345/01DE-3A", valid: false},
+		{name: "CNPJ repeated digits", tag: "cnpj", value: "00000000000000", valid: false},
+
+		{name: "CEP digits only", tag: "cep", value: "01310100", valid: true},
+		{name: "CEP formatted", tag: "cep", value: "01310-100", valid: true},
+		{name: "CEP zeros", tag: "cep", value: "00000-000", valid: false},
+		{name: "CEP wrong length", tag: "cep", value: "0131010", valid: false},
+
+		{name: "CNS definitive", tag: "cns", value: "123456789000005", valid: true},
+		{name: "CNS provisional with spaces", tag: "cns", value: "700 0000 0000 0005", valid: true},
+		{name: "CNS wrong check", tag: "cns", value: "123456789000006", valid: false},
+		{name: "CNS invalid first digit", tag: "cns", value: "300000000000005", valid: false},
+
+		{name: "Mobile phone digits only", tag: "br_phone", value: "11912345678", valid: true},
+		{name: "Mobile phone formatted with country code", tag: "br_phone", value: "+55 (11) 91234-5678", valid: true},
+		{name: "Landline formatted", tag: "br_phone", value: "(21) 3123-4567", valid: true},
+		{name: "Landline with country code", tag: "br_phone", value: "552131234567", valid: true},
+		{name: "Invalid area code", tag: "br_phone", value: "(20) 91234-5678", valid: false},
+		{name: "Mobile not starting with 9", tag: "br_phone", value: "11812345678", valid: false},
+		{name: "Landline starting with 9", tag: "br_phone", value: "1191234567", valid: false},
+		{name: "Too short", tag: "br_phone", value: "912345678", valid: false},
+	}
+
+	validator := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Var(tt.value, tt.tag)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBrazilianValidationTagsTranslations(t *testing.T) {
+	type Beneficiary struct {
+		CPF     string `validate:"cpf" custom:"CPF"`
+		CNPJ    string `validate:"cnpj" custom:"CNPJ"`
+		CEP     string `validate:"cep" custom:"CEP"`
+		CNS     string `validate:"cns" custom:"CNS"`
+		Phone   string `validate:"br_phone" custom:"Telefone"`
+		Company string `validate:"omitempty,cnpj" custom:"Empresa"`
+	}
+
+	input := Beneficiary{CPF: "1", CNPJ: "1", CEP: "1", CNS: "1", Phone: "1"}
+
+	tests := []struct {
+		name             string
+		locale           string
+		expectedMessages []string
+	}{
+		{
+			name:   "Portuguese",
+			locale: kit.LocalePtBR,
+			expectedMessages: []string{
+				"CPF deve ser um CPF válido",
+				"CNPJ deve ser um CNPJ válido",
+				"CEP deve ser um CEP válido",
+				"CNS deve ser um Cartão Nacional de Saúde válido",
+				"Telefone deve ser um telefone válido",
+			},
+		},
+		{
+			name:   "English",
+			locale: kit.LocaleEn,
+			expectedMessages: []string{
+				"CPF must be a valid CPF",
+				"CNPJ must be a valid CNPJ",
+				"CEP must be a valid CEP",
+				"CNS must be a valid National Health Card (CNS) number",
+				"Telefone must be a valid Brazilian phone number",
+			},
+		},
+	}
+
+	validator := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StructTranslatedLocale(input, tt.locale)
+
+			var validationErr *kit.ValidationErrors
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedMessages, validationErr.Validations())
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines context-aware validation: rules reading the authenticated user (company, company category and
+// permissions) from the request context, the built-in permission and company_category tags, and the helpers
+// reading the user from a context.
+
+package kit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContextValidator is implemented by validators able to run context-aware rules.
+// ParseRequestBody uses it to validate requests with the request context, which carries the authenticated user.
+type ContextValidator interface {
+	LocalizedValidator
+	StructTranslatedLocaleCtx(ctx context.Context, s interface{}, locale string) error
+}
+
+// contextRules are the built-in validation tags depending on the authenticated user.
+var contextRules = []struct {
+	tag      string
+	fn       func(ctx context.Context, param string) bool
+	messages map[string]string
+}{
+	{
+		tag: "permission",
+		fn:  HasPermission,
+		messages: map[string]string{
+			LocalePtBR: "{0} só pode ser informado por usuários com a permissão {1}",
+			LocaleEn:   "{0} can only be set by users with the {1} permission",
+			LocaleEs:   "{0} solo puede ser informado por usuarios con el permiso {1}",
+		},
+	},
+	{
+		tag: "company_category",
+		fn: func(ctx context.Context, param string) bool {
+			category, ok := UserCompanyCategory(ctx)
+			return ok && slices.Contains(strings.Fields(param), category)
+		},
+		messages: map[string]string{
+			LocalePtBR: "{0} só pode ser informado por empresas da categoria {1}",
+			LocaleEn:   "{0} can only be set by companies of category {1}",
+			LocaleEs:   "{0} solo puede ser informado por empresas de la categoría {1}",
+		},
+	},
+}
+
+// RegisterRuleCtx registers a context-aware field-level validation tag together with its message templates per
+// locale. The context is the one given to StructTranslatedCtx or, for requests parsed by ParseRequestBody and
+// the other Parse functions, the request context, from which UserCompany, UserCompanyCategory and UserPermissions
+// read the authenticated user. Templates follow the same rules as the ones of RegisterRule.
+func (v *Validate) RegisterRuleCtx(tag string, fn validator.FuncCtx, messages map[string]string, callEvenIfNull ...bool) error {
+	if err := requireDefaultMessage(tag, messages); err != nil {
+		return err
+	}
+
+	if err := v.RegisterValidationCtx(tag, fn, callEvenIfNull...); err != nil {
+		return fmt.Errorf("validation tag %q: %w", tag, err)
+	}
+
+	return v.registerTranslations(tag, messages)
+}
+
+// RegisterStructRuleCtx registers a context-aware struct-level validation for the given types, together with the
+// message templates of each tag it reports, as RegisterStructRule does.
+func (v *Validate) RegisterStructRuleCtx(fn validator.StructLevelFuncCtx, messages map[string]map[string]string, types ...interface{}) error {
+	return v.registerStructRule(func() { v.RegisterStructValidationCtx(fn, types...) }, messages)
+}
+
+// registerContextRules registers the validation tags depending on the authenticated user and their translations.
+// The fields of these tags may only be set, i.e. have a non-zero value, by the users the rules allow.
+func (v *Validate) registerContextRules() {
+	for _, rule := range contextRules {
+		fn := rule.fn
+		_ = v.RegisterRuleCtx(rule.tag, func(ctx context.Context, fl validator.FieldLevel) bool {
+			return fl.Field().IsZero() || fn(ctx, fl.Param())
+		}, rule.messages, true)
+	}
+}
+
+// UserCompany returns the company of the authenticated user, stored under CtxKeyUserCompany.
+func UserCompany(ctx context.Context) (string, bool) {
+	company, ok := ctx.Value(CtxKeyUserCompany).(string)
+	return company, ok && company != ""
+}
+
+// UserCompanyCategory returns the category of the company of the authenticated user,
+// stored under CtxKeyUserCompanyCategory.
+func UserCompanyCategory(ctx context.Context) (string, bool) {
+	category, ok := ctx.Value(CtxKeyUserCompanyCategory).(string)
+	return category, ok && category != ""
+}
+
+// UserPermissions returns the permissions of the authenticated user, stored under CtxKeyUserPermissions as a []string.
+func UserPermissions(ctx context.Context) []string {
+	permissions, _ := ctx.Value(CtxKeyUserPermissions).([]string)
+	return permissions
+}
+
+// HasPermission reports whether the authenticated user has the permission.
+func HasPermission(ctx context.Context, permission string) bool {
+	return slices.Contains(UserPermissions(ctx), permission)
+}
+
+// requestContext is the context the requests are validated with: the user context of the request,
+// whose values fall back to the locals of the request, where middlewares store the authenticated user.
+type requestContext struct {
+	context.Context
+	c *fiber.Ctx
+}
+
+// Value returns the value of the key in the user context or, if absent, in the locals of the request.
+func (rc requestContext) Value(key any) any {
+	if value := rc.Context.Value(key); value != nil {
+		return value
+	}
+	return rc.c.Locals(key)
+}
@@ -0,0 +1,222 @@
+package kit_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userContext returns a context carrying an authenticated user of the company, category and permissions.
+func userContext(company, category string, permissions ...string) context.Context {
+	ctx := context.WithValue(context.Background(), kit.CtxKeyUserCompany, company)
+	ctx = context.WithValue(ctx, kit.CtxKeyUserCompanyCategory, category)
+	return context.WithValue(ctx, kit.CtxKeyUserPermissions, permissions)
+}
+
+func TestUserContextHelpers(t *testing.T) {
+	ctx := userContext("acme", "provider", "claims:write", "claims:read")
+
+	company, ok := kit.UserCompany(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", company)
+
+	category, ok := kit.UserCompanyCategory(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "provider", category)
+
+	assert.Equal(t, []string{"claims:write", "claims:read"}, kit.UserPermissions(ctx))
+	assert.True(t, kit.HasPermission(ctx, "claims:read"))
+	assert.False(t, kit.HasPermission(ctx, "claims:approve"))
+
+	_, ok = kit.UserCompany(context.Background())
+	assert.False(t, ok)
+	_, ok = kit.UserCompanyCategory(context.Background())
+	assert.False(t, ok)
+	assert.Nil(t, kit.UserPermissions(context.Background()))
+}
+
+func TestContextValidationTags(t *testing.T) {
+	type Claim struct {
+		Amount          int    `json:"amount" validate:"required"`
+		Discount        *int   `json:"discount" validate:"permission=claims:discount" custom:"Desconto"`
+		Coparticipation string `json:"coparticipation" validate:"company_category=operator broker"`
+	}
+
+	discount := 10
+
+	tests := []struct {
+		name               string
+		ctx                context.Context
+		claim              Claim
+		locale             string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name:  "Restricted fields not set",
+			ctx:   context.Background(),
+			claim: Claim{Amount: 100},
+		},
+		{
+			name:  "Allowed user",
+			ctx:   userContext("acme", "broker", "claims:discount"),
+			claim: Claim{Amount: 100, Discount: &discount, Coparticipation: "30%"},
+		},
+		{
+			name:   "Forbidden user",
+			ctx:    userContext("acme", "provider", "claims:read"),
+			claim:  Claim{Amount: 100, Discount: &discount, Coparticipation: "30%"},
+			locale: kit.LocalePtBR,
+			expectedViolations: []kit.Violation{
+				{Field: "discount", Tag: "permission", Param: "claims:discount", Value: discount,
+					Message: "Desconto só pode ser informado por usuários com a permissão claims:discount"},
+				{Field: "coparticipation", Tag: "company_category", Param: "operator broker", Value: "30%",
+					Message: "coparticipation só pode ser informado por empresas da categoria operator broker"},
+			},
+		},
+		{
+			name:   "Anonymous user in English",
+			ctx:    context.Background(),
+			claim:  Claim{Amount: 100, Discount: &discount},
+			locale: kit.LocaleEn,
+			expectedViolations: []kit.Violation{
+				{Field: "discount", Tag: "permission", Param: "claims:discount", Value: discount,
+					Message: "Desconto can only be set by users with the claims:discount permission"},
+			},
+		},
+	}
+
+	validate := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.StructTranslatedLocaleCtx(tt.ctx, tt.claim, tt.locale)
+			if tt.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedViolations, validationErr.Violations())
+		})
+	}
+}
+
+func TestRegisterRuleCtx(t *testing.T) {
+	type Procedure struct {
+		Code string `json:"code" validate:"allowed_procedure" custom:"Procedimento"`
+	}
+
+	allowed := map[string][]string{"acme": {"10101012", "40301630"}}
+
+	validate := kit.NewValidator()
+	err := validate.RegisterRuleCtx("allowed_procedure", func(ctx context.Context, fl validator.FieldLevel) bool {
+		company, _ := kit.UserCompany(ctx)
+		return slices.Contains(allowed[company], fl.Field().String())
+	}, map[string]string{
+		kit.LocalePtBR: "{0} não é permitido para a sua empresa",
+		kit.LocaleEn:   "{0} is not allowed for your company",
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, validate.StructTranslatedCtx(userContext("acme", "provider"), Procedure{Code: "10101012"}))
+
+	err = validate.StructTranslatedCtx(userContext("other", "provider"), Procedure{Code: "10101012"})
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []kit.Violation{
+		{Field: "code", Tag: "allowed_procedure", Value: "10101012", Message: "Procedimento não é permitido para a sua empresa"},
+	}, validationErr.Violations())
+
+	err = validate.RegisterRuleCtx("no_message", func(context.Context, validator.FieldLevel) bool { return true },
+		map[string]string{kit.LocaleEn: "{0} is invalid"})
+	assert.EqualError(t, err, `validation tag "no_message": a pt_BR message is required`)
+
+	err = validate.RegisterRuleCtx("", func(context.Context, validator.FieldLevel) bool { return true },
+		map[string]string{kit.LocalePtBR: "{0} é inválido"})
+	assert.EqualError(t, err, `validation tag "": function Key cannot be empty`)
+}
+
+func TestRegisterStructRuleCtx(t *testing.T) {
+	type Authorization struct {
+		Company string `json:"company" validate:"required" custom:"Empresa"`
+	}
+
+	validate := kit.NewValidator()
+	err := validate.RegisterStructRuleCtx(func(ctx context.Context, sl validator.StructLevel) {
+		authorization := sl.Current().Interface().(Authorization)
+		if company, _ := kit.UserCompany(ctx); company != authorization.Company {
+			sl.ReportError(authorization.Company, "Empresa", "Company", "own_company", "")
+		}
+	}, map[string]map[string]string{
+		"own_company": {kit.LocalePtBR: "{0} deve ser a empresa do usuário"},
+	}, Authorization{})
+	require.NoError(t, err)
+
+	assert.NoError(t, validate.StructTranslatedCtx(userContext("acme", "provider"), Authorization{Company: "acme"}))
+
+	err = validate.StructTranslatedCtx(userContext("acme", "provider"), Authorization{Company: "other"})
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []kit.Violation{
+		{Field: "company", Tag: "own_company", Value: "other", Message: "Empresa deve ser a empresa do usuário"},
+	}, validationErr.Violations())
+}
+
+func TestParseRequestBodyWithUserContext(t *testing.T) {
+	type Claim struct {
+		Discount int `json:"discount" validate:"permission=claims:discount"`
+	}
+
+	tests := []struct {
+		name         string
+		setUser      func(c *fiber.Ctx)
+		expectedSlug string
+	}{
+		{
+			name: "Permission in locals",
+			setUser: func(c *fiber.Ctx) {
+				c.Locals(kit.CtxKeyUserPermissions, []string{"claims:discount"})
+			},
+		},
+		{
+			name: "Permission in user context",
+			setUser: func(c *fiber.Ctx) {
+				c.SetUserContext(userContext("acme", "operator", "claims:discount"))
+			},
+		},
+		{
+			name:         "Missing permission",
+			setUser:      func(c *fiber.Ctx) {},
+			expectedSlug: "request-validation",
+		},
+	}
+
+	v := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out Claim
+			err := parseRoute(t, fiber.MethodPost, "/contracts/1", `{"discount":10}`, nil, func(c *fiber.Ctx) error {
+				tt.setUser(c)
+				return kit.ParseRequestBody(&out, c, v)
+			})
+
+			if tt.expectedSlug == "" {
+				require.NoError(t, err)
+				assert.Equal(t, 10, out.Discount)
+				return
+			}
+
+			var httpError *kit.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, tt.expectedSlug, httpError.Slug)
+		})
+	}
+}
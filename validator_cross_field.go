@@ -0,0 +1,241 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines the built-in cross-field validation tags (after, after_or_equal, at_least_one_of,
+// mutually_exclusive and required_when), whose messages name the referenced fields by their `custom` labels.
+
+package kit
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// crossFieldRule is a validation tag referencing other fields of the same struct by their Go names in its param.
+// Its message templates receive the field as {0} and the params returned by params as {1}, {2}, and so on.
+type crossFieldRule struct {
+	tag      string
+	fn       validator.Func
+	params   func(param string, label func(name string) string) []string
+	messages map[string]string
+}
+
+// crossFieldRules are the built-in cross-field validation tags.
+var crossFieldRules = []crossFieldRule{
+	{
+		tag:    "after",
+		fn:     func(fl validator.FieldLevel) bool { return compareDates(fl, func(c int) bool { return c > 0 }) },
+		params: fieldLabels,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser posterior a {1}",
+			LocaleEn:   "{0} must be after {1}",
+			LocaleEs:   "{0} debe ser posterior a {1}",
+		},
+	},
+	{
+		tag:    "after_or_equal",
+		fn:     func(fl validator.FieldLevel) bool { return compareDates(fl, func(c int) bool { return c >= 0 }) },
+		params: fieldLabels,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser igual ou posterior a {1}",
+			LocaleEn:   "{0} must be on or after {1}",
+			LocaleEs:   "{0} debe ser igual o posterior a {1}",
+		},
+	},
+	{
+		tag:    "at_least_one_of",
+		fn:     isAtLeastOneOf,
+		params: fieldLabels,
+		messages: map[string]string{
+			LocalePtBR: "Informe {0} ou {1}",
+			LocaleEn:   "Provide {0} or {1}",
+			LocaleEs:   "Informe {0} o {1}",
+		},
+	},
+	{
+		tag:    "mutually_exclusive",
+		fn:     isMutuallyExclusive,
+		params: fieldLabels,
+		messages: map[string]string{
+			LocalePtBR: "{0} não pode ser informado junto com {1}",
+			LocaleEn:   "{0} cannot be provided together with {1}",
+			LocaleEs:   "{0} no puede ser informado junto con {1}",
+		},
+	},
+	{
+		tag: "required_when",
+		fn:  isRequiredWhen,
+		params: func(param string, label func(name string) string) []string {
+			name, values := requiredWhenParam(param)
+			return []string{label(name), strings.Join(values, ", ")}
+		},
+		messages: map[string]string{
+			LocalePtBR: "{0} é obrigatório quando {1} é {2}",
+			LocaleEn:   "{0} is required when {1} is {2}",
+			LocaleEs:   "{0} es obligatorio cuando {1} es {2}",
+		},
+	},
+}
+
+// crossFieldTags indexes the cross-field rules by tag.
+var crossFieldTags = func() map[string]crossFieldRule {
+	tags := make(map[string]crossFieldRule, len(crossFieldRules))
+	for _, rule := range crossFieldRules {
+		tags[rule.tag] = rule
+	}
+	return tags
+}()
+
+// registerCrossFieldRules registers the cross-field validation tags and their translations.
+// They are called for nil fields too, as their fields are optional unless a rule requires them.
+func (v *Validate) registerCrossFieldRules() {
+	for _, rule := range crossFieldRules {
+		_ = v.RegisterValidation(rule.tag, rule.fn, true)
+		for locale, trans := range v.translators {
+			message := localizedMessage(rule.messages, locale)
+			_ = v.RegisterTranslation(rule.tag, trans,
+				func(t ut.Translator) error {
+					return t.Add(rule.tag, message, true)
+				},
+				func(t ut.Translator, fe validator.FieldError) string {
+					return crossFieldMessage(t, fe, fe.Field(), rule.params(fe.Param(), func(name string) string { return name }))
+				},
+			)
+		}
+	}
+}
+
+// translateCrossField translates the error of a cross-field tag, naming the field by its path and the referenced
+// fields by their labels, resolved from the root type. It reports false for other tags.
+func translateCrossField(fe validator.FieldError, trans ut.Translator, root reflect.Type, path string) (string, bool) {
+	rule, ok := crossFieldTags[fe.Tag()]
+	if !ok {
+		return "", false
+	}
+
+	parent := parentType(root, fe.StructNamespace())
+	label := func(name string) string {
+		if parent != nil {
+			if field, ok := parent.FieldByName(name); ok {
+				return fieldLabel(field)
+			}
+		}
+		return name
+	}
+	return crossFieldMessage(trans, fe, path, rule.params(fe.Param(), label)), true
+}
+
+// crossFieldMessage translates the error of a cross-field tag with the field name and the params.
+func crossFieldMessage(trans ut.Translator, fe validator.FieldError, field string, params []string) string {
+	msg, err := trans.T(fe.Tag(), append([]string{field}, params...)...)
+	if err != nil {
+		return fe.Error()
+	}
+	return msg
+}
+
+// fieldLabels returns the labels of the space-separated field names of the param, joined by commas.
+func fieldLabels(param string, label func(name string) string) []string {
+	names := strings.Fields(param)
+	labels := make([]string, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, label(name))
+	}
+	return []string{strings.Join(labels, ", ")}
+}
+
+// compareDates reports whether the comparison of the date of the field with the date of the field named by the
+// param satisfies cmp. Fields that are empty or not dates are left to other tags and reported as valid.
+func compareDates(fl validator.FieldLevel, cmp func(c int) bool) bool {
+	date, ok := dateValue(fl.Field())
+	if !ok {
+		return true
+	}
+
+	other, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !found {
+		return true
+	}
+	otherDate, ok := dateValue(other)
+	if !ok {
+		return true
+	}
+
+	return cmp(date.Compare(otherDate))
+}
+
+// dateValue returns the date held by a time.Time field or by a string field in RFC 3339 or YYYY-MM-DD format.
+func dateValue(field reflect.Value) (time.Time, bool) {
+	if !isSet(field) {
+		return time.Time{}, false
+	}
+
+	switch {
+	case field.Type() == timeType:
+		return field.Interface().(time.Time), true
+	case field.Kind() == reflect.String:
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if date, err := time.Parse(layout, field.String()); err == nil {
+				return date, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// isAtLeastOneOf reports whether the field or one of the fields named by the param is set.
+func isAtLeastOneOf(fl validator.FieldLevel) bool {
+	if isSet(fl.Field()) {
+		return true
+	}
+	for _, name := range strings.Fields(fl.Param()) {
+		if other, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), name); found && isSet(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMutuallyExclusive reports whether the field is not set together with any of the fields named by the param.
+func isMutuallyExclusive(fl validator.FieldLevel) bool {
+	if !isSet(fl.Field()) {
+		return true
+	}
+	for _, name := range strings.Fields(fl.Param()) {
+		if other, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), name); found && isSet(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRequiredWhen reports whether the field is set when the field named by the param, e.g. "PersonType PJ",
+// holds one of the values that follow its name.
+func isRequiredWhen(fl validator.FieldLevel) bool {
+	name, values := requiredWhenParam(fl.Param())
+
+	other, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), name)
+	if !found || !isSet(other) || !other.CanInterface() || !slices.Contains(values, fmt.Sprint(other.Interface())) {
+		return true
+	}
+	return isSet(fl.Field())
+}
+
+// requiredWhenParam splits the param of the required_when tag into the field name and its values,
+// panicking on invalid params as validator does.
+func requiredWhenParam(param string) (string, []string) {
+	fields := strings.Fields(param)
+	if len(fields) < 2 {
+		panic(fmt.Sprintf("kit: invalid required_when param %q", param))
+	}
+	return fields[0], fields[1:]
+}
+
+// isSet reports whether the field has a non-zero value.
+func isSet(field reflect.Value) bool {
+	return field.IsValid() && !field.IsZero()
+}
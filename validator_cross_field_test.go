@@ -0,0 +1,145 @@
+package kit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arvo-health/kit"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossFieldValidationTags(t *testing.T) {
+	type Period struct {
+		Start    time.Time  `json:"start" custom:"Início da vigência"`
+		End      *time.Time `json:"end" validate:"after=Start" custom:"Fim da vigência"`
+		Renewal  string     `json:"renewal" validate:"after_or_equal=Birthday"`
+		Birthday string     `json:"birthday" custom:"Aniversário"`
+	}
+
+	type Contact struct {
+		Phone string `json:"phone" validate:"at_least_one_of=Email" custom:"Telefone"`
+		Email string `json:"email" custom:"E-mail"`
+	}
+
+	type Holder struct {
+		PersonType string  `json:"person_type" validate:"required,oneof=PF PJ" custom:"Tipo de pessoa"`
+		CPF        string  `json:"cpf" validate:"required_when=PersonType PF,mutually_exclusive=CNPJ"`
+		CNPJ       *string `json:"cnpj" validate:"required_when=PersonType PJ" custom:"CNPJ"`
+	}
+
+	type Contract struct {
+		Holder   Holder    `json:"holder" custom:"Titular"`
+		Periods  []Period  `json:"periods" validate:"dive" custom:"Período"`
+		Contacts []Contact `json:"contacts" validate:"dive"`
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+	after := start.AddDate(1, 0, 0)
+	cnpj := "11222333000181"
+
+	tests := []struct {
+		name               string
+		contract           Contract
+		locale             string
+		expectedViolations []kit.Violation
+	}{
+		{
+			name: "Valid",
+			contract: Contract{
+				Holder: Holder{PersonType: "PJ", CNPJ: &cnpj},
+				Periods: []Period{
+					{Start: start, End: &after, Renewal: "2025-03-10", Birthday: "2025-03-10"},
+					{Start: start, Renewal: "2025-03-10T10:00:00Z", Birthday: "2025-03-10"},
+				},
+				Contacts: []Contact{},
+			},
+		},
+		{
+			name: "Invalid in Portuguese",
+			contract: Contract{
+				Holder: Holder{PersonType: "PJ", CPF: "52998224725"},
+				Periods: []Period{
+					{Start: start, End: &after},
+					{Start: start, End: &before, Renewal: "2025-03-09", Birthday: "2025-03-10"},
+				},
+				Contacts: []Contact{{Email: "maria@example.com"}, {}},
+			},
+			expectedViolations: []kit.Violation{
+				{Field: "holder.cnpj", Tag: "required_when", Param: "PersonType PJ", Value: (*string)(nil),
+					Message: "Titular › CNPJ é obrigatório quando Tipo de pessoa é PJ"},
+				{Field: "periods[1].end", Tag: "after", Param: "Start", Value: before,
+					Message: "Período 2 › Fim da vigência deve ser posterior a Início da vigência"},
+				{Field: "periods[1].renewal", Tag: "after_or_equal", Param: "Birthday", Value: "2025-03-09",
+					Message: "periods[1].renewal deve ser igual ou posterior a Aniversário"},
+				{Field: "contacts[1].phone", Tag: "at_least_one_of", Param: "Email", Value: "",
+					Message: "Informe contacts[1] › Telefone ou E-mail"},
+			},
+		},
+		{
+			name: "Invalid in English",
+			contract: Contract{
+				Holder: Holder{PersonType: "PF", CPF: "52998224725", CNPJ: &cnpj},
+			},
+			locale: kit.LocaleEn,
+			expectedViolations: []kit.Violation{
+				{Field: "holder.cpf", Tag: "mutually_exclusive", Param: "CNPJ", Value: "52998224725",
+					Message: "holder.cpf cannot be provided together with CNPJ"},
+			},
+		},
+		{
+			name: "Required when in Spanish",
+			contract: Contract{
+				Holder: Holder{PersonType: "PF"},
+			},
+			locale: kit.LocaleEs,
+			expectedViolations: []kit.Violation{
+				{Field: "holder.cpf", Tag: "required_when", Param: "PersonType PF", Value: "",
+					Message: "holder.cpf es obligatorio cuando Tipo de pessoa es PF"},
+			},
+		},
+	}
+
+	validate := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.StructTranslatedLocale(tt.contract, tt.locale)
+			if tt.expectedViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedViolations, validationErr.Violations())
+		})
+	}
+}
+
+func TestCrossFieldValidationTagsWithoutLabels(t *testing.T) {
+	type Contact struct {
+		Phone  string `validate:"at_least_one_of=Email Mobile"`
+		Email  string
+		Mobile *string
+	}
+
+	validate := kit.NewValidator()
+
+	err := validate.StructTranslated(Contact{})
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Informe Phone ou Email, Mobile", validationErr.Violations()[0].Message)
+
+	var fieldErrs validator.ValidationErrors
+	require.ErrorAs(t, validate.Struct(Contact{}), &fieldErrs)
+	assert.Equal(t, "Informe Phone ou Email, Mobile", fieldErrs[0].Translate(validate.Translator))
+
+	assert.Panics(t, func() {
+		_ = validate.Struct(struct {
+			CPF string `validate:"required_when=PersonType"`
+		}{})
+	})
+}
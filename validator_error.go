@@ -5,38 +5,75 @@
 
 package kit
 
+import "encoding/json"
+
+// Violation describes a single validation failure, addressed by the JSON path of the rejected field.
+type Violation struct {
+	Field   string `json:"field,omitempty"`  // JSON path of the field, e.g. items[2].cpf.
+	Source  string `json:"source,omitempty"` // Request source of the field: body, query, path or header.
+	Row     int    `json:"row,omitempty"`    // Row of the field in CSV imports, counted from 1 at the header row.
+	Tag     string `json:"tag,omitempty"`    // Validation tag that failed, e.g. required.
+	Param   string `json:"param,omitempty"`  // Parameter of the validation tag, e.g. 18 for gte=18.
+	Value   any    `json:"value,omitempty"`  // Rejected value.
+	Message string `json:"message"`          // Translated validation message.
+}
+
+// UnmarshalJSON decodes a violation from its structured form or from a plain message string,
+// the compatibility format of validation details.
+func (v *Violation) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*v = Violation{Message: message}
+		return nil
+	}
+
+	type violation Violation // prevents recursion into UnmarshalJSON
+	return json.Unmarshal(data, (*violation)(v))
+}
+
 // ValidationErrors represents a structured validation error containing a message and details about specific violations.
 type ValidationErrors struct {
-	message     string
-	validations []string
+	message    string
+	violations []Violation
 }
 
 // NewValidationErrors creates a new instance of ValidationErrors with the specified message and optional validations.
 func NewValidationErrors(message string, validations ...string) *ValidationErrors {
-	return &ValidationErrors{
-		message:     message,
-		validations: validations,
-	}
+	e := &ValidationErrors{message: message}
+	e.Add(validations...)
+	return e
 }
 
 // Add appends one or more validation messages to the list of validations in the ValidationErrors instance.
 func (e *ValidationErrors) Add(validation ...string) {
-	e.validations = append(e.validations, validation...)
+	for _, v := range validation {
+		e.violations = append(e.violations, Violation{Message: v})
+	}
+}
+
+// AddViolations appends one or more structured violations to the ValidationErrors instance.
+func (e *ValidationErrors) AddViolations(violations ...Violation) {
+	e.violations = append(e.violations, violations...)
 }
 
-// Validations returns the slice of validation messages stored in the ValidationErrors instance.
+// Validations returns the validation messages stored in the ValidationErrors instance.
 func (e *ValidationErrors) Validations() []string {
-	return e.validations
+	return violationMessages(e.violations)
+}
+
+// Violations returns the structured violations stored in the ValidationErrors instance.
+func (e *ValidationErrors) Violations() []Violation {
+	return e.violations
 }
 
 // HasValidations checks if there are any validation errors present in the ValidationErrors instance.
 func (e *ValidationErrors) HasValidations() bool {
-	return len(e.validations) > 0
+	return len(e.violations) > 0
 }
 
 // HasNoValidations returns true if there are no validation messages in the ValidationErrors instance.
 func (e *ValidationErrors) HasNoValidations() bool {
-	return len(e.validations) == 0
+	return len(e.violations) == 0
 }
 
 // ErrorOrNil returns the ValidationErrors instance if there are validation errors; otherwise, it returns nil.
@@ -51,3 +88,25 @@ func (e *ValidationErrors) ErrorOrNil() error {
 func (e *ValidationErrors) Error() string {
 	return e.message
 }
+
+// setDefaultSource sets the source of the violations that do not report one.
+func (e *ValidationErrors) setDefaultSource(source string) {
+	for i := range e.violations {
+		if e.violations[i].Source == "" {
+			e.violations[i].Source = source
+		}
+	}
+}
+
+// violationMessages returns the messages of the given violations, or nil if there are none.
+func violationMessages(violations []Violation) []string {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.Message)
+	}
+	return messages
+}
@@ -79,3 +79,20 @@ func TestValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationErrorsViolations(t *testing.T) {
+	validationErrors := kit.NewValidationErrors("Validation failed", "Field A is required")
+	validationErrors.AddViolations(kit.Violation{
+		Field:   "items[0].cpf",
+		Tag:     "len",
+		Param:   "11",
+		Value:   "123",
+		Message: "CPF deve ter 11 caracteres",
+	})
+
+	assert.Equal(t, []kit.Violation{
+		{Message: "Field A is required"},
+		{Field: "items[0].cpf", Tag: "len", Param: "11", Value: "123", Message: "CPF deve ter 11 caracteres"},
+	}, validationErrors.Violations(), "Violations should match")
+	assert.Equal(t, []string{"Field A is required", "CPF deve ter 11 caracteres"}, validationErrors.Validations(), "Validations should match")
+}
@@ -0,0 +1,305 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines the built-in validation tags for uploaded files (file_max_size, file_mime and file_max_pages),
+// applied to *multipart.FileHeader fields, along with their translated messages.
+
+package kit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	fileSizeRegex = regexp.MustCompile(`^(\d+)\s*(B|KB|MB|GB)?$`)
+
+	fileHeaderType = reflect.TypeFor[multipart.FileHeader]()
+)
+
+// fileSizeUnits are the multipliers of the units accepted by the file_max_size tag.
+var fileSizeUnits = map[string]int64{"": 1, "B": 1, "KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}
+
+// fileRules are the built-in validation tags for uploaded files.
+var fileRules = []struct {
+	tag      string
+	fn       func(fh *multipart.FileHeader, param string) bool
+	messages map[string]string
+}{
+	{
+		tag: "file_max_size",
+		fn:  isFileMaxSize,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ter no máximo {1}",
+			LocaleEn:   "{0} must be at most {1}",
+			LocaleEs:   "{0} debe tener como máximo {1}",
+		},
+	},
+	{
+		tag: "file_mime",
+		fn:  isFileMIME,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um arquivo do tipo {1}",
+			LocaleEn:   "{0} must be a file of type {1}",
+			LocaleEs:   "{0} debe ser un archivo de tipo {1}",
+		},
+	},
+}
+
+// maxPagesMessages are the message templates of the file_max_pages tag.
+var maxPagesMessages = map[string]string{
+	LocalePtBR: "{0} deve ter no máximo {1} páginas",
+	LocaleEn:   "{0} must have at most {1} pages",
+	LocaleEs:   "{0} debe tener como máximo {1} páginas",
+}
+
+// unreadablePDFKey is the translation key of the message of file_max_pages for PDFs whose pages cannot be counted.
+const unreadablePDFKey = "file_max_pages_unreadable"
+
+// unreadablePDFMessages are the message templates of file_max_pages for PDFs whose pages cannot be counted.
+var unreadablePDFMessages = map[string]string{
+	LocalePtBR: "{0} deve ser um PDF legível",
+	LocaleEn:   "{0} must be a readable PDF",
+	LocaleEs:   "{0} debe ser un PDF legible",
+}
+
+// registerFileRules registers the validation tags for uploaded files and their translations.
+// Fields of any type other than *multipart.FileHeader are reported as invalid.
+func (v *Validate) registerFileRules() {
+	for _, rule := range fileRules {
+		fn := rule.fn
+		_ = v.RegisterRule(rule.tag, func(fl validator.FieldLevel) bool {
+			fh, ok := fileHeader(fl.Field())
+			return ok && fn(fh, fl.Param())
+		}, rule.messages)
+	}
+
+	_ = v.RegisterRuleCtx("file_max_pages", func(ctx context.Context, fl validator.FieldLevel) bool {
+		fh, ok := fileHeader(fl.Field())
+		return ok && isFileMaxPages(ctx, fh, fl.Param())
+	}, maxPagesMessages)
+	for locale, trans := range v.translators {
+		_ = trans.Add(unreadablePDFKey, localizedMessage(unreadablePDFMessages, locale), true)
+	}
+	v.messageKeys["file_max_pages"] = func(ctx context.Context, fe validator.FieldError) string {
+		if fh, ok := fileHeader(reflect.ValueOf(fe.Value())); ok {
+			if _, err := cachedFilePages(ctx, fh); errors.Is(err, errUnreadablePDF) {
+				return unreadablePDFKey
+			}
+		}
+		return fe.Tag()
+	}
+}
+
+// CheckFileTags checks the params of the file_max_size and file_max_pages tags of the given structs, and of the
+// structs nested in them, returning an error for each invalid one. Validations panic on invalid params, as validator
+// does for its own tags, so call it where the request types are registered, e.g. at startup, to fail there instead.
+func CheckFileTags(structs ...any) error {
+	var errs []error
+	seen := map[reflect.Type]bool{}
+	for _, s := range structs {
+		errs = append(errs, checkFileTags(reflect.TypeOf(s), seen))
+	}
+	return errors.Join(errs...)
+}
+
+// checkFileTags checks the params of the file tags of the fields of the type t and of the types nested in it.
+func checkFileTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	t = valueType(t)
+	if t == nil || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return checkFileTags(t.Elem(), seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	var errs []error
+	for i := range t.NumField() {
+		field := t.Field(i)
+		for _, rule := range strings.FieldsFunc(field.Tag.Get("validate"), func(r rune) bool { return r == ',' || r == '|' }) {
+			var err error
+			switch tag, param, _ := strings.Cut(rule, "="); tag {
+			case "file_max_size":
+				_, err = parseFileSize(param)
+			case "file_max_pages":
+				_, err = parseMaxPages(param)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err))
+			}
+		}
+		errs = append(errs, checkFileTags(field.Type, seen))
+	}
+	return errors.Join(errs...)
+}
+
+// fileHeader returns the multipart.FileHeader held by the field value.
+func fileHeader(field reflect.Value) (*multipart.FileHeader, bool) {
+	if !field.IsValid() {
+		return nil, false
+	}
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil, false
+		}
+		field = field.Elem()
+	}
+	if field.Type() != fileHeaderType {
+		return nil, false
+	}
+
+	fh := field.Interface().(multipart.FileHeader)
+	return &fh, true
+}
+
+// isFileMaxSize reports whether the file is at most the size of the param, in bytes or with a B, KB, MB or GB unit.
+func isFileMaxSize(fh *multipart.FileHeader, param string) bool {
+	limit, err := parseFileSize(param)
+	if err != nil {
+		panic(err.Error())
+	}
+	return fh.Size <= limit
+}
+
+// isFileMIME reports whether the MIME type sniffed from the content of the file is one of the space-separated
+// MIME types of the param, which may end with a wildcard subtype such as image/*. The Content-Type sent by the
+// client is not trusted.
+func isFileMIME(fh *multipart.FileHeader, param string) bool {
+	f, err := fh.Open()
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	detected, err := mimetype.DetectReader(f)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range strings.Fields(param) {
+		for m := detected; m != nil; m = m.Parent() {
+			if prefix, wildcard := strings.CutSuffix(allowed, "/*"); wildcard {
+				if strings.HasPrefix(m.String(), prefix+"/") {
+					return true
+				}
+			} else if m.Is(allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isFileMaxPages reports whether the file has at most the number of pages of the param. PDF pages are read from
+// the page tree, and PDFs whose pages cannot be counted fail with their own message; other files count as a single page.
+func isFileMaxPages(ctx context.Context, fh *multipart.FileHeader, param string) bool {
+	limit, err := parseMaxPages(param)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	pages, err := cachedFilePages(ctx, fh)
+	return err == nil && pages <= limit
+}
+
+// filePagesKey is the context key of the filePagesCache of a validation.
+type filePagesKey struct{}
+
+// filePagesCache holds the page counts of the files of a validation, so that choosing the message of
+// file_max_pages reuses the count of the rule instead of reading the file again.
+type filePagesCache map[fileID]filePagesResult
+
+// fileID identifies an uploaded file by its header, including the copies of the header, which share its
+// MIME header.
+type fileID struct {
+	header   uintptr
+	filename string
+	size     int64
+}
+
+// filePagesResult is the page count of a file, or the error counting them.
+type filePagesResult struct {
+	pages int
+	err   error
+}
+
+// withFilePagesCache returns a copy of the context holding an empty filePagesCache.
+func withFilePagesCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, filePagesKey{}, filePagesCache{})
+}
+
+// cachedFilePages counts the pages of the file as filePages does, once per file for contexts holding a
+// filePagesCache.
+func cachedFilePages(ctx context.Context, fh *multipart.FileHeader) (int, error) {
+	cache, _ := ctx.Value(filePagesKey{}).(filePagesCache)
+	id := fileID{header: reflect.ValueOf(fh.Header).Pointer(), filename: fh.Filename, size: fh.Size}
+	if result, ok := cache[id]; ok {
+		return result.pages, result.err
+	}
+
+	pages, err := filePages(fh)
+	if cache != nil {
+		cache[id] = filePagesResult{pages: pages, err: err}
+	}
+	return pages, err
+}
+
+// filePages counts the pages of the file, returning an error wrapping errUnreadablePDF for PDFs whose pages
+// cannot be counted.
+func filePages(fh *multipart.FileHeader) (int, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	detected, err := mimetype.DetectReader(f)
+	if err != nil {
+		return 0, err
+	}
+	if !detected.Is("application/pdf") {
+		return 1, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return readPDFPages(f)
+}
+
+// parseMaxPages parses the param of the file_max_pages tag, a positive number of pages.
+func parseMaxPages(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("kit: invalid file_max_pages param %q", s)
+	}
+	return n, nil
+}
+
+// parseFileSize parses a size in bytes or with a B, KB, MB or GB unit.
+func parseFileSize(s string) (int64, error) {
+	m := fileSizeRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("kit: invalid file size %q", s)
+	}
+
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("kit: invalid file size %q", s)
+	}
+	return n * fileSizeUnits[m[2]], nil
+}
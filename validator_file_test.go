@@ -0,0 +1,245 @@
+package kit_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPDF = []byte("%PDF-1.4\n" +
+		"1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+		"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> endobj\n" +
+		"3 0 obj << /Type /Page /Parent 2 0 R >> endobj\n" +
+		"4 0 obj << /Type/Page /Parent 2 0 R >> endobj\n" +
+		"%%EOF\n")
+	testUpdatedPDF = append(slices.Clip(testPDF), []byte(
+		"2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj\n"+
+			"trailer << /Size 5 /Root 1 0 R /Prev 9 >>\n"+
+			"%%EOF\n")...)
+	testCompressedPDF = objectStreamPDF(3)
+	testUnknownPDF    = []byte("%PDF-1.5\n1 0 obj << /Filter /FlateDecode >> stream\nxyz\nendstream endobj\n%%EOF\n")
+	testPNG           = append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), make([]byte, 32)...)
+)
+
+// objectStreamPDF returns a PDF with the given number of pages whose catalog and page tree are compressed
+// in an object stream, as PDF 1.5 writers do.
+func objectStreamPDF(pages int) []byte {
+	return paddedObjectStreamPDF(pages, 1, 0)
+}
+
+// paddedObjectStreamPDF returns a PDF like the one of objectStreamPDF, whose catalog and page tree are repeated
+// in the given number of object streams, each padded to decompress to at least padding bytes.
+func paddedObjectStreamPDF(pages, streams, padding int) []byte {
+	catalog := "<< /Type /Catalog /Pages 2 0 R >>"
+	pageTree := fmt.Sprintf("<< /Type /Pages /Kids [] /Count %d >>", pages)
+	header := fmt.Sprintf("1 0 2 %d ", len(catalog)+1)
+
+	var stream bytes.Buffer
+	w := zlib.NewWriter(&stream)
+	_, _ = w.Write([]byte(header + catalog + " " + pageTree))
+	_, _ = w.Write(bytes.Repeat([]byte(" "), padding))
+	_ = w.Close()
+
+	pdf := []byte("%PDF-1.5\n")
+	for i := range streams {
+		pdf = fmt.Appendf(pdf, "%d 0 obj << /Type /ObjStm /N 2 /First %d /Filter /FlateDecode /Length %d >> stream\n"+
+			"%s\nendstream endobj\n", 10+i, len(header), stream.Len(), stream.Bytes())
+	}
+	return fmt.Appendf(pdf, "6 0 obj << /Type /XRef /Size 7 /Root 1 0 R /Length 0 >> stream\n\nendstream endobj\n"+
+		"startxref\n0\n%%%%EOF\n")
+}
+
+// newFileHeader creates the multipart.FileHeader of a file uploaded with the given name and content.
+func newFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["file"][0]
+}
+
+// validateField validates a struct holding the value in a field with the validate tag,
+// since validator only applies tags to struct values when they are fields.
+func validateField(validator *kit.Validate, value any, tag string) error {
+	typ := reflect.StructOf([]reflect.StructField{{
+		Name: "Field",
+		Type: reflect.TypeOf(value),
+		Tag:  reflect.StructTag(`validate:"` + tag + `"`),
+	}})
+
+	s := reflect.New(typ).Elem()
+	s.Field(0).Set(reflect.ValueOf(value))
+	return validator.Struct(s.Interface())
+}
+
+func TestFileValidationTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		file    string
+		content []byte
+		valid   bool
+	}{
+		{name: "Size within limit", tag: "file_max_size=1KB", file: "a.pdf", content: testPDF, valid: true},
+		{name: "Size in bytes", tag: "file_max_size=10", file: "a.pdf", content: testPDF, valid: false},
+		{name: "Size over limit", tag: "file_max_size=1B", file: "a.pdf", content: testPDF, valid: false},
+
+		{name: "Allowed MIME type", tag: "file_mime=application/pdf image/png", file: "a.pdf", content: testPDF, valid: true},
+		{name: "Wildcard MIME type", tag: "file_mime=image/*", file: "a.png", content: testPNG, valid: true},
+		{name: "MIME type sniffed, not taken from the name", tag: "file_mime=application/pdf", file: "a.pdf", content: testPNG, valid: false},
+		{name: "Parent MIME type", tag: "file_mime=text/plain", file: "a.csv", content: []byte("a,b\n1,2\n"), valid: true},
+
+		{name: "PDF pages within limit", tag: "file_max_pages=2", file: "a.pdf", content: testPDF, valid: true},
+		{name: "PDF pages over limit", tag: "file_max_pages=1", file: "a.pdf", content: testPDF, valid: false},
+		{name: "PDF page count from an object stream", tag: "file_max_pages=3", file: "a.pdf", content: testCompressedPDF, valid: true},
+		{name: "PDF page count from an object stream over limit", tag: "file_max_pages=2", file: "a.pdf", content: testCompressedPDF, valid: false},
+		{name: "PDF page count from the last incremental update", tag: "file_max_pages=1", file: "a.pdf", content: testUpdatedPDF, valid: true},
+		{name: "PDF without readable pages", tag: "file_max_pages=100", file: "a.pdf", content: testUnknownPDF, valid: false},
+		{name: "PDF object stream within the read limit", tag: "file_max_pages=3", file: "a.pdf", content: paddedObjectStreamPDF(3, 1, 40<<20), valid: true},
+		{name: "PDF object streams over the read limit together", tag: "file_max_pages=3", file: "a.pdf", content: paddedObjectStreamPDF(3, 2, 40<<20), valid: false},
+		{name: "Image counts as a single page", tag: "file_max_pages=1", file: "a.png", content: testPNG, valid: true},
+	}
+
+	validator := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateField(validator, newFileHeader(t, tt.file, tt.content), tt.tag)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestFileValidationTagsOnOtherTypes(t *testing.T) {
+	validator := kit.NewValidator()
+
+	assert.Error(t, validateField(validator, "a.pdf", "file_max_size=1MB"))
+	assert.Error(t, validateField(validator, struct{ Name string }{}, "file_mime=application/pdf"))
+	assert.Error(t, validateField(validator, multipart.FileHeader{Filename: "missing.pdf"}, "file_mime=application/pdf"))
+	assert.Error(t, validateField(validator, multipart.FileHeader{Filename: "missing.pdf"}, "file_max_pages=1"))
+}
+
+func TestFileValidationTagsInvalidParams(t *testing.T) {
+	validator := kit.NewValidator()
+	file := newFileHeader(t, "a.pdf", testPDF)
+
+	assert.PanicsWithValue(t, `kit: invalid file size "10XB"`, func() { _ = validateField(validator, file, "file_max_size=10XB") })
+	assert.PanicsWithValue(t, `kit: invalid file size "99999999999999999999"`, func() { _ = validateField(validator, file, "file_max_size=99999999999999999999") })
+	assert.PanicsWithValue(t, `kit: invalid file_max_pages param "many"`, func() { _ = validateField(validator, file, "file_max_pages=many") })
+}
+
+func TestCheckFileTags(t *testing.T) {
+	type Attachment struct {
+		File *multipart.FileHeader `validate:"file_max_pages=0"`
+	}
+	type Upload struct {
+		Report      *multipart.FileHeader   `validate:"required,file_max_size=10MB,file_max_pages=20"`
+		Scan        *multipart.FileHeader   `validate:"omitempty,file_max_size=10XB|file_mime=image/png"`
+		Attachments []Attachment            `validate:"dive"`
+		Exams       []*multipart.FileHeader `validate:"dive,file_max_pages=many"`
+	}
+
+	require.NoError(t, kit.CheckFileTags(struct {
+		Report *multipart.FileHeader `validate:"file_max_size=1MB,file_max_pages=2"`
+	}{}))
+
+	err := kit.CheckFileTags(Upload{}, &Upload{})
+	require.Error(t, err)
+	assert.Equal(t, `Upload.Scan: kit: invalid file size "10XB"`+"\n"+
+		`Attachment.File: kit: invalid file_max_pages param "0"`+"\n"+
+		`Upload.Exams: kit: invalid file_max_pages param "many"`, err.Error())
+}
+
+func TestFileValidationTagsTranslations(t *testing.T) {
+	type Claim struct {
+		Report      *multipart.FileHeader   `validate:"required,file_max_size=1B,file_mime=image/png,file_max_pages=1" custom:"Laudo"`
+		Attachments []*multipart.FileHeader `validate:"max=1,dive,file_mime=image/*" custom:"Anexo"`
+	}
+
+	pdf := newFileHeader(t, "laudo.pdf", testPDF)
+	input := Claim{Report: pdf, Attachments: []*multipart.FileHeader{pdf}}
+
+	validator := kit.NewValidator()
+
+	t.Run("First failing rule per field", func(t *testing.T) {
+		err := validator.StructTranslatedLocale(input, kit.LocaleEn)
+
+		var validationErr *kit.ValidationErrors
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []kit.Violation{
+			{Field: "Report", Tag: "file_max_size", Param: "1B", Value: "laudo.pdf", Message: "Laudo must be at most 1B"},
+			{Field: "Attachments[0]", Tag: "file_mime", Param: "image/*", Value: "laudo.pdf", Message: "Anexo 1 must be a file of type image/*"},
+		}, validationErr.Violations())
+	})
+
+	tests := []struct {
+		name            string
+		locale          string
+		input           any
+		expectedMessage string
+	}{
+		{
+			name:   "MIME type in Portuguese",
+			locale: kit.LocalePtBR,
+			input: struct {
+				Report *multipart.FileHeader `validate:"file_mime=image/png" custom:"Laudo"`
+			}{Report: pdf},
+			expectedMessage: "Laudo deve ser um arquivo do tipo image/png",
+		},
+		{
+			name:   "Pages in Spanish",
+			locale: kit.LocaleEs,
+			input: struct {
+				Report *multipart.FileHeader `validate:"file_max_pages=1" custom:"Laudo"`
+			}{Report: pdf},
+			expectedMessage: "Laudo debe tener como máximo 1 páginas",
+		},
+		{
+			name:   "Unreadable PDF in English",
+			locale: kit.LocaleEn,
+			input: struct {
+				Report *multipart.FileHeader `validate:"file_max_pages=100" custom:"Laudo"`
+			}{Report: newFileHeader(t, "laudo.pdf", testUnknownPDF)},
+			expectedMessage: "Laudo must be a readable PDF",
+		},
+		{
+			name:   "Size in Portuguese",
+			locale: kit.LocalePtBR,
+			input: struct {
+				Report *multipart.FileHeader `validate:"file_max_size=10" custom:"Laudo"`
+			}{Report: pdf},
+			expectedMessage: "Laudo deve ter no máximo 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StructTranslatedLocale(tt.input, tt.locale)
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, []string{tt.expectedMessage}, validationErr.Validations())
+		})
+	}
+}
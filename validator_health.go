@@ -0,0 +1,126 @@
+// Package kit provides struct validation utilities using `go-playground/validator`.
+// This file defines the built-in validation tags for healthcare codes (cid10, tuss, cbo and ans),
+// backed by the reference tables, and for professional council registrations (crm and coren).
+
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	crmRegex   = regexp.MustCompile(`^(?:CRM[-/ ]?([A-Z]{2})[- ]?(\d{1,6})|(\d{1,6})[-/ ]?([A-Z]{2}))$`)
+	corenRegex = regexp.MustCompile(`^(?:COREN[-/ ]?([A-Z]{2})[- ]?(\d{1,9})|(\d{1,9})[-/ ]?([A-Z]{2}))$`)
+
+	// brazilianStates are the federative units (UF) of Brazil.
+	brazilianStates = map[string]struct{}{
+		"AC": {}, "AL": {}, "AP": {}, "AM": {}, "BA": {}, "CE": {}, "DF": {}, "ES": {}, "GO": {},
+		"MA": {}, "MT": {}, "MS": {}, "MG": {}, "PA": {}, "PB": {}, "PR": {}, "PE": {}, "PI": {},
+		"RJ": {}, "RN": {}, "RS": {}, "RO": {}, "RR": {}, "SC": {}, "SP": {}, "SE": {}, "TO": {},
+	}
+)
+
+// referenceMessages are the messages of the validation tags of the reference tables, indexed by table name.
+var referenceMessages = map[string]map[string]string{
+	"cid10": {
+		LocalePtBR: "{0} deve ser um código CID-10 válido",
+		LocaleEn:   "{0} must be a valid ICD-10 (CID-10) code",
+		LocaleEs:   "{0} debe ser un código CIE-10 (CID-10) válido",
+	},
+	"tuss": {
+		LocalePtBR: "{0} deve ser um código TUSS válido",
+		LocaleEn:   "{0} must be a valid TUSS procedure code",
+		LocaleEs:   "{0} debe ser un código de procedimiento TUSS válido",
+	},
+	"cbo": {
+		LocalePtBR: "{0} deve ser um código CBO válido",
+		LocaleEn:   "{0} must be a valid CBO occupation code",
+		LocaleEs:   "{0} debe ser un código de ocupación CBO válido",
+	},
+	"ans": {
+		LocalePtBR: "{0} deve ser um registro ANS válido",
+		LocaleEn:   "{0} must be a valid ANS operator registry number",
+		LocaleEs:   "{0} debe ser un registro de operadora ANS válido",
+	},
+}
+
+// healthRules are the built-in validation tags for professional council registrations.
+var healthRules = []stringRule{
+	{
+		tag: "crm",
+		fn:  isCRM,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um registro CRM válido com número e UF",
+			LocaleEn:   "{0} must be a valid CRM registration with number and state",
+			LocaleEs:   "{0} debe ser un registro CRM válido con número y estado",
+		},
+	},
+	{
+		tag: "coren",
+		fn:  isCOREN,
+		messages: map[string]string{
+			LocalePtBR: "{0} deve ser um registro COREN válido com número e UF",
+			LocaleEn:   "{0} must be a valid COREN registration with number and state",
+			LocaleEs:   "{0} debe ser un registro COREN válido con número y estado",
+		},
+	},
+}
+
+// RegisterReferenceTables registers the validation tags of the reference tables (cid10, tuss, cbo and ans),
+// named after the tables and backed by them, so codes are validated against the tables as loaded at validation
+// time. NewValidator registers the embedded tables (CID10Codes, TUSSCodes, CBOCodes and ANSOperators), which this
+// replaces, e.g. with tables kept per validator. Tables without codes, or without built-in messages, are not
+// registered and return an error. Like the other Register methods, it must be called before the validator is used.
+func (v *Validate) RegisterReferenceTables(tables ...*ReferenceTable) error {
+	var errs []error
+	for _, table := range tables {
+		messages, ok := referenceMessages[table.Name()]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("reference table %s: no built-in messages, register it with RegisterRule", table.Name()))
+		case table.Len() == 0:
+			errs = append(errs, fmt.Errorf("reference table %s: no codes loaded", table.Name()))
+		default:
+			v.registerStringRules([]stringRule{{tag: table.Name(), fn: table.Valid, messages: messages}})
+			v.referenceTables[table.Name()] = table
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isCRM reports whether s is a medical council registration with its number and UF,
+// as "123456/SP", "123456-SP" or "CRM-SP 123456".
+func isCRM(s string) bool {
+	return isCouncilRegistration(crmRegex, s)
+}
+
+// isCOREN reports whether s is a nursing council registration with its number and UF,
+// as "123456/SP", "123456-SP" or "COREN-SP 123456".
+func isCOREN(s string) bool {
+	return isCouncilRegistration(corenRegex, s)
+}
+
+// isCouncilRegistration reports whether s matches the council registration format, with a non-zero number
+// and a valid UF. The format captures either the UF and number, or the number and UF.
+func isCouncilRegistration(format *regexp.Regexp, s string) bool {
+	m := format.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return false
+	}
+
+	uf, number := m[1], m[2]
+	if uf == "" {
+		number, uf = m[3], m[4]
+	}
+
+	return isBrazilianState(uf) && strings.Trim(number, "0") != ""
+}
+
+// isBrazilianState reports whether s is the abbreviation of a Brazilian federative unit.
+func isBrazilianState(s string) bool {
+	_, ok := brazilianStates[strings.ToUpper(s)]
+	return ok
+}
@@ -0,0 +1,162 @@
+package kit_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/arvo-health/kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthcareValidationTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		tag   string
+		value string
+		valid bool
+	}{
+		{name: "CID-10 category", tag: "cid10", value: "E11", valid: true},
+		{name: "CID-10 subcategory", tag: "cid10", value: "E11.9", valid: true},
+		{name: "CID-10 unknown", tag: "cid10", value: "E11.8", valid: false},
+		{name: "CID-10 malformed", tag: "cid10", value: "E1", valid: false},
+
+		{name: "TUSS procedure", tag: "tuss", value: "40304361", valid: true},
+		{name: "TUSS unknown", tag: "tuss", value: "40304362", valid: false},
+
+		{name: "CBO occupation", tag: "cbo", value: "2235-05", valid: true},
+		{name: "CBO unknown", tag: "cbo", value: "9999-99", valid: false},
+
+		{name: "ANS registry", tag: "ans", value: "005711", valid: true},
+		{name: "ANS unknown", tag: "ans", value: "000000", valid: false},
+
+		{name: "CRM number and UF", tag: "crm", value: "123456/SP", valid: true},
+		{name: "CRM number and UF with hyphen", tag: "crm", value: "52123-rj", valid: true},
+		{name: "CRM prefixed", tag: "crm", value: "CRM-MG 12345", valid: true},
+		{name: "CRM prefixed with slash", tag: "crm", value: "CRM/DF 1234", valid: true},
+		{name: "CRM invalid UF", tag: "crm", value: "123456/XX", valid: false},
+		{name: "CRM without UF", tag: "crm", value: "123456", valid: false},
+		{name: "CRM zero number", tag: "crm", value: "000000/SP", valid: false},
+		{name: "CRM number too long", tag: "crm", value: "1234567/SP", valid: false},
+
+		{name: "COREN number and UF", tag: "coren", value: "123456789/BA", valid: true},
+		{name: "COREN prefixed", tag: "coren", value: "COREN-SP 654321", valid: true},
+		{name: "COREN with CRM prefix", tag: "coren", value: "CRM-SP 654321", valid: false},
+	}
+
+	validator := healthValidator(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Var(tt.value, tt.tag)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHealthcareValidationTagsTranslations(t *testing.T) {
+	type Claim struct {
+		Diagnosis  string `validate:"cid10" custom:"Diagnóstico"`
+		Procedure  string `validate:"tuss" custom:"Procedimento"`
+		Occupation string `validate:"cbo" custom:"CBO"`
+		Operator   string `validate:"ans" custom:"Operadora"`
+		Doctor     string `validate:"crm" custom:"CRM"`
+		Nurse      string `validate:"coren" custom:"COREN"`
+	}
+
+	input := Claim{Diagnosis: "X", Procedure: "X", Occupation: "X", Operator: "X", Doctor: "X", Nurse: "X"}
+
+	tests := []struct {
+		name             string
+		locale           string
+		expectedMessages []string
+	}{
+		{
+			name:   "Portuguese",
+			locale: kit.LocalePtBR,
+			expectedMessages: []string{
+				"Diagnóstico deve ser um código CID-10 válido",
+				"Procedimento deve ser um código TUSS válido",
+				"CBO deve ser um código CBO válido",
+				"Operadora deve ser um registro ANS válido",
+				"CRM deve ser um registro CRM válido com número e UF",
+				"COREN deve ser um registro COREN válido com número e UF",
+			},
+		},
+		{
+			name:   "Spanish",
+			locale: kit.LocaleEs,
+			expectedMessages: []string{
+				"Diagnóstico debe ser un código CIE-10 (CID-10) válido",
+				"Procedimento debe ser un código de procedimiento TUSS válido",
+				"CBO debe ser un código de ocupación CBO válido",
+				"Operadora debe ser un registro de operadora ANS válido",
+				"CRM debe ser un registro CRM válido con número y estado",
+				"COREN debe ser un registro COREN válido con número y estado",
+			},
+		},
+	}
+
+	validator := healthValidator(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StructTranslatedLocale(input, tt.locale)
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedMessages, validationErr.Validations())
+		})
+	}
+}
+
+func TestHealthcareValidationTagsRefreshedTable(t *testing.T) {
+	table := kit.NewCBOTable()
+	require.NoError(t, table.Load(strings.NewReader("2231-01;Médico acupunturista\n")))
+
+	validator := kit.NewValidator()
+	require.NoError(t, validator.RegisterReferenceTables(table))
+
+	assert.NoError(t, validator.Var("2231-01", "cbo"))
+	assert.Error(t, validator.Var("2251-25", "cbo"))
+
+	require.NoError(t, table.Load(strings.NewReader("2251-25;Médico clínico\n")))
+
+	assert.Error(t, validator.Var("2231-01", "cbo"))
+	assert.NoError(t, validator.Var("2251-25", "cbo"))
+}
+
+func TestRegisterReferenceTables(t *testing.T) {
+	validator := kit.NewValidator()
+
+	assert.NoError(t, validator.Var("J45", "cid10"), "The embedded tables are registered by NewValidator")
+
+	err := validator.RegisterReferenceTables(kit.NewCID10Table(),
+		kit.NewReferenceTable("codes", regexp.MustCompile(`^\d{3}$`), strings.TrimSpace))
+	assert.EqualError(t, err, "reference table cid10: no codes loaded\n"+
+		"reference table codes: no built-in messages, register it with RegisterRule")
+	assert.NoError(t, validator.Var("J45", "cid10"), "Empty tables do not replace the registered ones")
+}
+
+// referenceTables returns the reference tables loaded with the embedded snapshots.
+func referenceTables(t *testing.T) []*kit.ReferenceTable {
+	t.Helper()
+
+	tables := []*kit.ReferenceTable{kit.CID10Codes, kit.TUSSCodes, kit.CBOCodes, kit.ANSOperators}
+	for _, table := range tables {
+		require.NotZero(t, table.Len(), table.Name())
+	}
+	return tables
+}
+
+// healthValidator returns a validator with the embedded reference tables registered.
+func healthValidator(t *testing.T) *kit.Validate {
+	t.Helper()
+
+	return kit.NewValidator()
+}
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/arvo-health/kit"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Struct to test validation logic.
@@ -86,3 +88,259 @@ func TestStructTranslated(t *testing.T) {
 		})
 	}
 }
+
+func TestStructTranslatedViolations(t *testing.T) {
+	type Item struct {
+		CPF string `json:"cpf" validate:"required" custom:"CPF"`
+	}
+
+	type Address struct {
+		ZipCode string `json:"zip_code" validate:"len=8" custom:"CEP"`
+	}
+
+	type Order struct {
+		Address
+		Items []Item `json:"items" validate:"dive"`
+		Age   int    `validate:"gte=18" custom:"Idade"`
+	}
+
+	input := Order{
+		Address: Address{ZipCode: "123"},
+		Items:   []Item{{CPF: "1"}, {CPF: "2"}, {CPF: ""}},
+		Age:     16,
+	}
+
+	validator := kit.NewValidator()
+	err := validator.StructTranslated(&input)
+
+	var validationErr *kit.ValidationErrors
+	assert.ErrorAs(t, err, &validationErr)
+	assert.ElementsMatch(t, []kit.Violation{
+		{Field: "zip_code", Tag: "len", Param: "8", Value: "123", Message: "CEP deve ter 8 caracteres"},
+		{Field: "items[2].cpf", Tag: "required", Value: "", Message: "items[2] › CPF é um campo obrigatório"},
+		{Field: "Age", Tag: "gte", Param: "18", Value: 16, Message: "Idade deve ser 18 ou superior"},
+	}, validationErr.Violations())
+}
+
+func TestStructTranslatedLocale(t *testing.T) {
+	input := ExampleStruct{Name: "", Email: "john.doe@example.com", Age: 25}
+
+	tests := []struct {
+		name            string
+		locale          string
+		expectedMessage string
+	}{
+		{name: "Portuguese", locale: kit.LocalePtBR, expectedMessage: "Nome é um campo obrigatório"},
+		{name: "English", locale: kit.LocaleEn, expectedMessage: "Nome is a required field"},
+		{name: "Spanish", locale: kit.LocaleEs, expectedMessage: "Nome es un campo requerido"},
+		{name: "Unsupported locale falls back to Portuguese", locale: "fr", expectedMessage: "Nome é um campo obrigatório"},
+	}
+
+	validator := kit.NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StructTranslatedLocale(input, tt.locale)
+
+			var validationErr *kit.ValidationErrors
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, []string{tt.expectedMessage}, validationErr.Validations())
+		})
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	type Plan struct {
+		Code string `validate:"plan_code=3" custom:"Plano"`
+	}
+
+	validate := kit.NewValidator()
+	err := validate.RegisterRule("plan_code", func(fl validator.FieldLevel) bool {
+		return len(fl.Field().String()) == 3
+	}, map[string]string{
+		kit.LocalePtBR: "{0} deve ter {1} caracteres",
+		kit.LocaleEn:   "{0} must have {1} characters",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		locale          string
+		expectedMessage string
+	}{
+		{name: "Portuguese", locale: kit.LocalePtBR, expectedMessage: "Plano deve ter 3 caracteres"},
+		{name: "English", locale: kit.LocaleEn, expectedMessage: "Plano must have 3 characters"},
+		{name: "Missing locale falls back to Portuguese", locale: kit.LocaleEs, expectedMessage: "Plano deve ter 3 caracteres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.StructTranslatedLocale(Plan{Code: "GOLD"}, tt.locale)
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, []kit.Violation{
+				{Field: "Code", Tag: "plan_code", Param: "3", Value: "GOLD", Message: tt.expectedMessage},
+			}, validationErr.Violations())
+		})
+	}
+
+	assert.NoError(t, validate.StructTranslated(Plan{Code: "PRO"}))
+}
+
+func TestRegisterRuleNamesTheFieldByItsPath(t *testing.T) {
+	type Item struct {
+		Value int `json:"value" validate:"even" custom:"valor"`
+	}
+	type Order struct {
+		Items []Item `json:"items" validate:"dive" custom:"Item"`
+	}
+
+	validate := kit.NewValidator()
+	err := validate.RegisterRule("even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}, map[string]string{kit.LocalePtBR: "O valor de {0} deve ser par"})
+	require.NoError(t, err)
+
+	err = validate.StructTranslated(Order{Items: []Item{{Value: 2}, {Value: 3}}})
+
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{"O valor de Item 2 › valor deve ser par"}, validationErr.Validations())
+}
+
+func TestRegisterRuleOverridesBuiltInTranslation(t *testing.T) {
+	validate := kit.NewValidator()
+	err := validate.RegisterRule("cpf", func(fl validator.FieldLevel) bool {
+		return fl.Field().String() == "ok"
+	}, map[string]string{kit.LocalePtBR: "{0} inválido"})
+	require.NoError(t, err)
+
+	err = validate.StructTranslated(struct {
+		Document string `validate:"cpf" custom:"Documento"`
+	}{Document: "52998224725"})
+
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{"Documento inválido"}, validationErr.Validations())
+}
+
+func TestRegisterRuleErrors(t *testing.T) {
+	validate := kit.NewValidator()
+	fn := func(validator.FieldLevel) bool { return true }
+
+	tests := []struct {
+		name          string
+		register      func() error
+		expectedError string
+	}{
+		{
+			name: "Rule without default locale message",
+			register: func() error {
+				return validate.RegisterRule("rule", fn, map[string]string{kit.LocaleEn: "{0} is invalid"})
+			},
+			expectedError: `validation tag "rule": a pt_BR message is required`,
+		},
+		{
+			name:          "Rule with restricted tag",
+			register:      func() error { return validate.RegisterRule("", fn, map[string]string{kit.LocalePtBR: "{0} inválido"}) },
+			expectedError: `validation tag "": function Key cannot be empty`,
+		},
+		{
+			name:          "Alias without default locale message",
+			register:      func() error { return validate.RegisterAliasRule("alias", "required", nil) },
+			expectedError: `validation tag "alias": a pt_BR message is required`,
+		},
+		{
+			name: "Alias with restricted name",
+			register: func() error {
+				return validate.RegisterAliasRule("omitempty", "required", map[string]string{kit.LocalePtBR: "{0} inválido"})
+			},
+			expectedError: `validation tag "omitempty": Alias 'omitempty' either contains restricted characters or is the same as a restricted tag needed for normal operation`,
+		},
+		{
+			name: "Struct rule without default locale message",
+			register: func() error {
+				return validate.RegisterStructRule(func(validator.StructLevel) {}, map[string]map[string]string{"rule": nil}, ExampleStruct{})
+			},
+			expectedError: `validation tag "rule": a pt_BR message is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.register(), tt.expectedError)
+		})
+	}
+}
+
+func TestRegisterStructRule(t *testing.T) {
+	type Period struct {
+		Start int `validate:"required" custom:"Início"`
+		End   int `validate:"required" custom:"Fim"`
+	}
+
+	validate := kit.NewValidator()
+	err := validate.RegisterStructRule(func(sl validator.StructLevel) {
+		period := sl.Current().Interface().(Period)
+		if period.End < period.Start {
+			sl.ReportError(period.End, "Fim", "End", "period_order", "Início")
+		}
+	}, map[string]map[string]string{
+		"period_order": {
+			kit.LocalePtBR: "{0} deve ser posterior a {1}",
+			kit.LocaleEn:   "{0} must be after {1}",
+		},
+	}, Period{})
+	require.NoError(t, err)
+
+	err = validate.StructTranslatedLocale(Period{Start: 10, End: 5}, kit.LocaleEn)
+
+	var validationErr *kit.ValidationErrors
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []kit.Violation{
+		{Field: "End", Tag: "period_order", Param: "Início", Value: 5, Message: "Fim must be after Início"},
+	}, validationErr.Violations())
+
+	assert.NoError(t, validate.StructTranslated(Period{Start: 5, End: 10}))
+}
+
+func TestRegisterAliasRule(t *testing.T) {
+	type Beneficiary struct {
+		Document string `validate:"document" custom:"Documento"`
+	}
+
+	validate := kit.NewValidator()
+	err := validate.RegisterAliasRule("document", "cpf|cns", map[string]string{
+		kit.LocalePtBR: "{0} deve ser um CPF ou CNS válido",
+		kit.LocaleEs:   "{0} debe ser un CPF o CNS válido",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		document        string
+		locale          string
+		expectedMessage string
+	}{
+		{name: "CPF", document: "52998224725", locale: kit.LocalePtBR},
+		{name: "CNS", document: "700000000000005", locale: kit.LocalePtBR},
+		{name: "Invalid in Portuguese", document: "1", locale: kit.LocalePtBR, expectedMessage: "Documento deve ser um CPF ou CNS válido"},
+		{name: "Invalid in Spanish", document: "1", locale: kit.LocaleEs, expectedMessage: "Documento debe ser un CPF o CNS válido"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.StructTranslatedLocale(Beneficiary{Document: tt.document}, tt.locale)
+			if tt.expectedMessage == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *kit.ValidationErrors
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, []string{tt.expectedMessage}, validationErr.Validations())
+			assert.Equal(t, "document", validationErr.Violations()[0].Tag)
+		})
+	}
+}